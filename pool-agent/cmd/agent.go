@@ -211,6 +211,10 @@ func countPooledInstances(instances []api.Instance, resourceTypeName, imageAlias
 }
 
 func isPooledInstance(i api.Instance, resourceTypeName, imageAlias string) bool {
+	// quarantined instance is kept for investigation, it is never used as stock
+	if i.Config[configKeyQuarantined] != "" {
+		return false
+	}
 	// Check if the feature flag is enabled to exclude Running instances
 	if featureflag.IsEnabled(featureflag.CountWithoutRunning) {
 		if i.StatusCode != api.Frozen {
@@ -259,6 +263,10 @@ func (a *Agent) CalculateToDeleteInstances(s []api.Instance, disabledResourceTyp
 		if i.Config[configKeyResourceType] == "" || i.Config[configKeyImageAlias] != a.Image[imageKey].Config.ImageAlias {
 			continue
 		}
+		// quarantined instance is deleted by server after retention
+		if i.Config[configKeyQuarantined] != "" {
+			continue
+		}
 		l := slog.With(slog.String("instance", i.Name), slog.String("imageKey", imageKey))
 		if a.isZombieInstance(i, imageKey) {
 			toDelete = append(toDelete, i)
@@ -106,6 +106,18 @@ func (s *AgentSuite) SetupSuite() {
 				},
 			},
 		},
+		{
+			Name:       "quarantined_running",
+			StatusCode: api.Running,
+			InstancePut: api.InstancePut{
+				Config: map[string]string{
+					cmd.ConfigKeyResourceType: "typeD",
+					cmd.ConfigKeyImageAlias:   "ubuntu:focal",
+					cmd.ConfigKeyRunnerName:   "",
+					cmd.ConfigKeyQuarantined:  "setup_script_failed",
+				},
+			},
+		},
 	}, nil)
 }
 
@@ -148,6 +160,10 @@ func (s *AgentSuite) TestCalculateToDeleteInstances() {
 			name: "disabled_stock_frozen",
 			want: true,
 		},
+		{
+			name: "quarantined_running",
+			want: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -305,6 +321,23 @@ func TestIsPooledInstance(t *testing.T) {
 			imageAlias:       "ubuntu:focal",
 			want:             false,
 		},
+		{
+			name: "Quarantined",
+			instance: api.Instance{
+				StatusCode: api.Frozen,
+				InstancePut: api.InstancePut{
+					Config: map[string]string{
+						cmd.ConfigKeyResourceType: "typeA",
+						cmd.ConfigKeyImageAlias:   "ubuntu:focal",
+						cmd.ConfigKeyRunnerName:   "runner1",
+						cmd.ConfigKeyQuarantined:  "unfreeze_failed",
+					},
+				},
+			},
+			resourceTypeName: "typeA",
+			imageAlias:       "ubuntu:focal",
+			want:             false,
+		},
 		{
 			name: "All matched - Running",
 			instance: api.Instance{
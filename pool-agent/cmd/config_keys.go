@@ -4,5 +4,6 @@ const (
 	configKeyResourceType = "user.myshoes_resource_type"
 	configKeyImageAlias   = "user.myshoes_image_alias"
 	configKeyRunnerName   = "user.myshoes_runner_name"
+	configKeyQuarantined  = "user.myshoes_quarantined"
 	cacheKeyImageServer   = "image_server"
 )
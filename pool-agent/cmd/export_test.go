@@ -4,6 +4,7 @@ var IsPooledInstance = isPooledInstance
 var ConfigKeyResourceType = configKeyResourceType
 var ConfigKeyImageAlias = configKeyImageAlias
 var ConfigKeyRunnerName = configKeyRunnerName
+var ConfigKeyQuarantined = configKeyQuarantined
 
 type Instances = instances
 type ImageStatus = imageStatus
@@ -10,6 +10,7 @@ import (
 	proto_go "github.com/whywaita/myshoes/api/proto.go"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -21,6 +22,54 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type SetupLogSource int32
+
+const (
+	// journal of myshoes-setup unit
+	SetupLogSource_SETUP_LOG_SOURCE_JOURNAL SetupLogSource = 0
+	// console output of instance, it includes output of other units
+	SetupLogSource_SETUP_LOG_SOURCE_CONSOLE SetupLogSource = 1
+)
+
+// Enum value maps for SetupLogSource.
+var (
+	SetupLogSource_name = map[int32]string{
+		0: "SETUP_LOG_SOURCE_JOURNAL",
+		1: "SETUP_LOG_SOURCE_CONSOLE",
+	}
+	SetupLogSource_value = map[string]int32{
+		"SETUP_LOG_SOURCE_JOURNAL": 0,
+		"SETUP_LOG_SOURCE_CONSOLE": 1,
+	}
+)
+
+func (x SetupLogSource) Enum() *SetupLogSource {
+	p := new(SetupLogSource)
+	*p = x
+	return p
+}
+
+func (x SetupLogSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SetupLogSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_enumTypes[0].Descriptor()
+}
+
+func (SetupLogSource) Type() protoreflect.EnumType {
+	return &file_shoeslxdmulti_shoes_lxd_multi_proto_enumTypes[0]
+}
+
+func (x SetupLogSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SetupLogSource.Descriptor instead.
+func (SetupLogSource) EnumDescriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{0}
+}
+
 // req / resp
 type AddInstanceRequest struct {
 	state         protoimpl.MessageState
@@ -35,6 +84,8 @@ type AddInstanceRequest struct {
 	// Deprecated: Marked as deprecated in shoeslxdmulti/shoes-lxd-multi.proto.
 	ImageAlias string `protobuf:"bytes,5,opt,name=image_alias,json=imageAlias,proto3" json:"image_alias,omitempty"`
 	OsVersion  string `protobuf:"bytes,7,opt,name=os_version,json=osVersion,proto3" json:"os_version,omitempty"`
+	// secret_environment is passed to setup script as environment variables, it is not written to disk or logged
+	SecretEnvironment map[string]string `protobuf:"bytes,8,rep,name=secret_environment,json=secretEnvironment,proto3" json:"secret_environment,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *AddInstanceRequest) Reset() {
@@ -119,6 +170,13 @@ func (x *AddInstanceRequest) GetOsVersion() string {
 	return ""
 }
 
+func (x *AddInstanceRequest) GetSecretEnvironment() map[string]string {
+	if x != nil {
+		return x.SecretEnvironment
+	}
+	return nil
+}
+
 type AddInstanceResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -283,171 +341,2451 @@ func (*DeleteInstanceResponse) Descriptor() ([]byte, []int) {
 	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{3}
 }
 
-var File_shoeslxdmulti_shoes_lxd_multi_proto protoreflect.FileDescriptor
+type DeleteByRunnerNameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_shoeslxdmulti_shoes_lxd_multi_proto_rawDesc = []byte{
-	0x0a, 0x23, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2f,
-	0x73, 0x68, 0x6f, 0x65, 0x73, 0x2d, 0x6c, 0x78, 0x64, 0x2d, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d,
-	0x75, 0x6c, 0x74, 0x69, 0x1a, 0x16, 0x77, 0x68, 0x79, 0x77, 0x61, 0x69, 0x74, 0x61, 0x2f, 0x6d,
-	0x79, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9c, 0x02, 0x0a,
-	0x12, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x5f, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72,
-	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x74, 0x75, 0x70, 0x5f, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x65, 0x74, 0x75,
-	0x70, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x43, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x6f, 0x75,
-	0x72, 0x63, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e,
-	0x2e, 0x77, 0x68, 0x79, 0x77, 0x61, 0x69, 0x74, 0x61, 0x2e, 0x6d, 0x79, 0x73, 0x68, 0x6f, 0x65,
-	0x73, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0c,
-	0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06,
-	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x61,
-	0x62, 0x65, 0x6c, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x68,
-	0x6f, 0x73, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65,
-	0x5f, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x02, 0x18, 0x01,
-	0x52, 0x0a, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x1d, 0x0a, 0x0a,
-	0x6f, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x09, 0x6f, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xb3, 0x01, 0x0a, 0x13,
-	0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x64, 0x12, 0x1d,
-	0x0a, 0x0a, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x09, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a,
-	0x0a, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x43, 0x0a, 0x0d,
-	0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x77, 0x68, 0x79, 0x77, 0x61, 0x69, 0x74, 0x61, 0x2e, 0x6d,
-	0x79, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54,
-	0x79, 0x70, 0x65, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70,
-	0x65, 0x22, 0x55, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61,
-	0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x6c,
-	0x6f, 0x75, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6c,
-	0x6f, 0x75, 0x64, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f,
-	0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x22, 0x18, 0x0a, 0x16, 0x44, 0x65, 0x6c, 0x65,
-	0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x32, 0xc8, 0x01, 0x0a, 0x0d, 0x53, 0x68, 0x6f, 0x65, 0x73, 0x4c, 0x58, 0x44, 0x4d,
-	0x75, 0x6c, 0x74, 0x69, 0x12, 0x56, 0x0a, 0x0b, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61,
-	0x6e, 0x63, 0x65, 0x12, 0x21, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75,
-	0x6c, 0x74, 0x69, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78,
-	0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e,
-	0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5f, 0x0a, 0x0e,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x24,
-	0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d,
-	0x75, 0x6c, 0x74, 0x69, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61,
-	0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x3c, 0x5a,
-	0x3a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x68, 0x79, 0x77,
-	0x61, 0x69, 0x74, 0x61, 0x2f, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x2d, 0x6c, 0x78, 0x64, 0x2d, 0x6d,
-	0x75, 0x6c, 0x74, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x6f, 0x2f, 0x73, 0x68,
-	0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	RunnerName  string   `protobuf:"bytes,1,opt,name=runner_name,json=runnerName,proto3" json:"runner_name,omitempty"`
+	TargetHosts []string `protobuf:"bytes,2,rep,name=target_hosts,json=targetHosts,proto3" json:"target_hosts,omitempty"`
 }
 
-var (
-	file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescOnce sync.Once
-	file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescData = file_shoeslxdmulti_shoes_lxd_multi_proto_rawDesc
-)
+func (x *DeleteByRunnerNameRequest) Reset() {
+	*x = DeleteByRunnerNameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP() []byte {
-	file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescOnce.Do(func() {
-		file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescData = protoimpl.X.CompressGZIP(file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescData)
-	})
-	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescData
+func (x *DeleteByRunnerNameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
-var file_shoeslxdmulti_shoes_lxd_multi_proto_goTypes = []interface{}{
-	(*AddInstanceRequest)(nil),     // 0: shoeslxdmulti.AddInstanceRequest
-	(*AddInstanceResponse)(nil),    // 1: shoeslxdmulti.AddInstanceResponse
-	(*DeleteInstanceRequest)(nil),  // 2: shoeslxdmulti.DeleteInstanceRequest
-	(*DeleteInstanceResponse)(nil), // 3: shoeslxdmulti.DeleteInstanceResponse
-	(proto_go.ResourceType)(0),     // 4: whywaita.myshoes.ResourceType
+func (*DeleteByRunnerNameRequest) ProtoMessage() {}
+
+func (x *DeleteByRunnerNameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_shoeslxdmulti_shoes_lxd_multi_proto_depIdxs = []int32{
-	4, // 0: shoeslxdmulti.AddInstanceRequest.resource_type:type_name -> whywaita.myshoes.ResourceType
-	4, // 1: shoeslxdmulti.AddInstanceResponse.resource_type:type_name -> whywaita.myshoes.ResourceType
-	0, // 2: shoeslxdmulti.ShoesLXDMulti.AddInstance:input_type -> shoeslxdmulti.AddInstanceRequest
-	2, // 3: shoeslxdmulti.ShoesLXDMulti.DeleteInstance:input_type -> shoeslxdmulti.DeleteInstanceRequest
-	1, // 4: shoeslxdmulti.ShoesLXDMulti.AddInstance:output_type -> shoeslxdmulti.AddInstanceResponse
-	3, // 5: shoeslxdmulti.ShoesLXDMulti.DeleteInstance:output_type -> shoeslxdmulti.DeleteInstanceResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+
+// Deprecated: Use DeleteByRunnerNameRequest.ProtoReflect.Descriptor instead.
+func (*DeleteByRunnerNameRequest) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{4}
 }
 
-func init() { file_shoeslxdmulti_shoes_lxd_multi_proto_init() }
-func file_shoeslxdmulti_shoes_lxd_multi_proto_init() {
-	if File_shoeslxdmulti_shoes_lxd_multi_proto != nil {
-		return
+func (x *DeleteByRunnerNameRequest) GetRunnerName() string {
+	if x != nil {
+		return x.RunnerName
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddInstanceRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddInstanceResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return ""
+}
+
+func (x *DeleteByRunnerNameRequest) GetTargetHosts() []string {
+	if x != nil {
+		return x.TargetHosts
+	}
+	return nil
+}
+
+type DeleteByRunnerNameResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// cloud_id of deleted instance
+	CloudId string `protobuf:"bytes,1,opt,name=cloud_id,json=cloudId,proto3" json:"cloud_id,omitempty"`
+}
+
+func (x *DeleteByRunnerNameResponse) Reset() {
+	*x = DeleteByRunnerNameResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteByRunnerNameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteByRunnerNameResponse) ProtoMessage() {}
+
+func (x *DeleteByRunnerNameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteInstanceRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteByRunnerNameResponse.ProtoReflect.Descriptor instead.
+func (*DeleteByRunnerNameResponse) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteByRunnerNameResponse) GetCloudId() string {
+	if x != nil {
+		return x.CloudId
+	}
+	return ""
+}
+
+// BatchError is error of an item in batch request
+type BatchError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// gRPC status code
+	Code    int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *BatchError) Reset() {
+	*x = BatchError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchError) ProtoMessage() {}
+
+func (x *BatchError) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteInstanceResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchError.ProtoReflect.Descriptor instead.
+func (*BatchError) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BatchError) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type AddInstancesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Instances []*AddInstanceRequest `protobuf:"bytes,1,rep,name=instances,proto3" json:"instances,omitempty"`
+}
+
+func (x *AddInstancesRequest) Reset() {
+	*x = AddInstancesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddInstancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddInstancesRequest) ProtoMessage() {}
+
+func (x *AddInstancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddInstancesRequest.ProtoReflect.Descriptor instead.
+func (*AddInstancesRequest) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AddInstancesRequest) GetInstances() []*AddInstanceRequest {
+	if x != nil {
+		return x.Instances
+	}
+	return nil
+}
+
+type AddInstancesResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RunnerName string `protobuf:"bytes,1,opt,name=runner_name,json=runnerName,proto3" json:"runner_name,omitempty"`
+	// set if succeeded
+	Instance *AddInstanceResponse `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
+	// set if failed
+	Error *BatchError `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *AddInstancesResult) Reset() {
+	*x = AddInstancesResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddInstancesResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddInstancesResult) ProtoMessage() {}
+
+func (x *AddInstancesResult) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddInstancesResult.ProtoReflect.Descriptor instead.
+func (*AddInstancesResult) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AddInstancesResult) GetRunnerName() string {
+	if x != nil {
+		return x.RunnerName
+	}
+	return ""
+}
+
+func (x *AddInstancesResult) GetInstance() *AddInstanceResponse {
+	if x != nil {
+		return x.Instance
+	}
+	return nil
+}
+
+func (x *AddInstancesResult) GetError() *BatchError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type AddInstancesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// results in same order as instances of request
+	Results []*AddInstancesResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *AddInstancesResponse) Reset() {
+	*x = AddInstancesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddInstancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddInstancesResponse) ProtoMessage() {}
+
+func (x *AddInstancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddInstancesResponse.ProtoReflect.Descriptor instead.
+func (*AddInstancesResponse) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AddInstancesResponse) GetResults() []*AddInstancesResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type DeleteInstancesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Instances []*DeleteInstanceRequest `protobuf:"bytes,1,rep,name=instances,proto3" json:"instances,omitempty"`
+}
+
+func (x *DeleteInstancesRequest) Reset() {
+	*x = DeleteInstancesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteInstancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteInstancesRequest) ProtoMessage() {}
+
+func (x *DeleteInstancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteInstancesRequest.ProtoReflect.Descriptor instead.
+func (*DeleteInstancesRequest) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteInstancesRequest) GetInstances() []*DeleteInstanceRequest {
+	if x != nil {
+		return x.Instances
+	}
+	return nil
+}
+
+type DeleteInstancesResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CloudId string `protobuf:"bytes,1,opt,name=cloud_id,json=cloudId,proto3" json:"cloud_id,omitempty"`
+	// set if failed
+	Error *BatchError `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *DeleteInstancesResult) Reset() {
+	*x = DeleteInstancesResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteInstancesResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteInstancesResult) ProtoMessage() {}
+
+func (x *DeleteInstancesResult) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteInstancesResult.ProtoReflect.Descriptor instead.
+func (*DeleteInstancesResult) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteInstancesResult) GetCloudId() string {
+	if x != nil {
+		return x.CloudId
+	}
+	return ""
+}
+
+func (x *DeleteInstancesResult) GetError() *BatchError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type DeleteInstancesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// results in same order as instances of request
+	Results []*DeleteInstancesResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *DeleteInstancesResponse) Reset() {
+	*x = DeleteInstancesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteInstancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteInstancesResponse) ProtoMessage() {}
+
+func (x *DeleteInstancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteInstancesResponse.ProtoReflect.Descriptor instead.
+func (*DeleteInstancesResponse) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteInstancesResponse) GetResults() []*DeleteInstancesResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type CordonHostRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+}
+
+func (x *CordonHostRequest) Reset() {
+	*x = CordonHostRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CordonHostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CordonHostRequest) ProtoMessage() {}
+
+func (x *CordonHostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CordonHostRequest.ProtoReflect.Descriptor instead.
+func (*CordonHostRequest) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CordonHostRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+type CordonHostResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CordonHostResponse) Reset() {
+	*x = CordonHostResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CordonHostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CordonHostResponse) ProtoMessage() {}
+
+func (x *CordonHostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CordonHostResponse.ProtoReflect.Descriptor instead.
+func (*CordonHostResponse) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{14}
+}
+
+type UncordonHostRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+}
+
+func (x *UncordonHostRequest) Reset() {
+	*x = UncordonHostRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UncordonHostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UncordonHostRequest) ProtoMessage() {}
+
+func (x *UncordonHostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UncordonHostRequest.ProtoReflect.Descriptor instead.
+func (*UncordonHostRequest) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UncordonHostRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+type UncordonHostResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UncordonHostResponse) Reset() {
+	*x = UncordonHostResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UncordonHostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UncordonHostResponse) ProtoMessage() {}
+
+func (x *UncordonHostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UncordonHostResponse.ProtoReflect.Descriptor instead.
+func (*UncordonHostResponse) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{16}
+}
+
+type ListJournalRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// filter by runner name if set
+	RunnerName string `protobuf:"bytes,1,opt,name=runner_name,json=runnerName,proto3" json:"runner_name,omitempty"`
+	// filter by started time if set
+	Since *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	Until *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	// max number of entries, newest entries are returned. 0 is unlimited.
+	Limit int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *ListJournalRequest) Reset() {
+	*x = ListJournalRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListJournalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJournalRequest) ProtoMessage() {}
+
+func (x *ListJournalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJournalRequest.ProtoReflect.Descriptor instead.
+func (*ListJournalRequest) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListJournalRequest) GetRunnerName() string {
+	if x != nil {
+		return x.RunnerName
+	}
+	return ""
+}
+
+func (x *ListJournalRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListJournalRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *ListJournalRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type JournalEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// allocate or delete
+	Operation    string                 `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	RunnerName   string                 `protobuf:"bytes,3,opt,name=runner_name,json=runnerName,proto3" json:"runner_name,omitempty"`
+	Host         string                 `protobuf:"bytes,4,opt,name=host,proto3" json:"host,omitempty"`
+	InstanceName string                 `protobuf:"bytes,5,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	ImageAlias   string                 `protobuf:"bytes,6,opt,name=image_alias,json=imageAlias,proto3" json:"image_alias,omitempty"`
+	ResourceType string                 `protobuf:"bytes,7,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`
+	StartedAt    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	AllocatedAt  *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=allocated_at,json=allocatedAt,proto3" json:"allocated_at,omitempty"`
+	FinishedAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	// pending, succeeded, failed or interrupted
+	Outcome string `protobuf:"bytes,11,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	Message string `protobuf:"bytes,12,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *JournalEntry) Reset() {
+	*x = JournalEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JournalEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JournalEntry) ProtoMessage() {}
+
+func (x *JournalEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JournalEntry.ProtoReflect.Descriptor instead.
+func (*JournalEntry) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *JournalEntry) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *JournalEntry) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetRunnerName() string {
+	if x != nil {
+		return x.RunnerName
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetImageAlias() string {
+	if x != nil {
+		return x.ImageAlias
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *JournalEntry) GetAllocatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AllocatedAt
+	}
+	return nil
+}
+
+func (x *JournalEntry) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *JournalEntry) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *JournalEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ListJournalResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*JournalEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *ListJournalResponse) Reset() {
+	*x = ListJournalResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListJournalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJournalResponse) ProtoMessage() {}
+
+func (x *ListJournalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJournalResponse.ProtoReflect.Descriptor instead.
+func (*ListJournalResponse) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListJournalResponse) GetEntries() []*JournalEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetSetupLogRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CloudId     string         `protobuf:"bytes,1,opt,name=cloud_id,json=cloudId,proto3" json:"cloud_id,omitempty"`
+	TargetHosts []string       `protobuf:"bytes,2,rep,name=target_hosts,json=targetHosts,proto3" json:"target_hosts,omitempty"`
+	Source      SetupLogSource `protobuf:"varint,3,opt,name=source,proto3,enum=shoeslxdmulti.SetupLogSource" json:"source,omitempty"`
+	// follow log until the client cancels, only supported in journal
+	Follow bool `protobuf:"varint,4,opt,name=follow,proto3" json:"follow,omitempty"`
+	// number of lines from the end of journal. 0 is all lines.
+	TailLines int32 `protobuf:"varint,5,opt,name=tail_lines,json=tailLines,proto3" json:"tail_lines,omitempty"`
+}
+
+func (x *GetSetupLogRequest) Reset() {
+	*x = GetSetupLogRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSetupLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSetupLogRequest) ProtoMessage() {}
+
+func (x *GetSetupLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSetupLogRequest.ProtoReflect.Descriptor instead.
+func (*GetSetupLogRequest) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetSetupLogRequest) GetCloudId() string {
+	if x != nil {
+		return x.CloudId
+	}
+	return ""
+}
+
+func (x *GetSetupLogRequest) GetTargetHosts() []string {
+	if x != nil {
+		return x.TargetHosts
+	}
+	return nil
+}
+
+func (x *GetSetupLogRequest) GetSource() SetupLogSource {
+	if x != nil {
+		return x.Source
+	}
+	return SetupLogSource_SETUP_LOG_SOURCE_JOURNAL
+}
+
+func (x *GetSetupLogRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+func (x *GetSetupLogRequest) GetTailLines() int32 {
+	if x != nil {
+		return x.TailLines
+	}
+	return 0
+}
+
+type GetSetupLogResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// chunk of log
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *GetSetupLogResponse) Reset() {
+	*x = GetSetupLogResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSetupLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSetupLogResponse) ProtoMessage() {}
+
+func (x *GetSetupLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSetupLogResponse.ProtoReflect.Descriptor instead.
+func (*GetSetupLogResponse) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetSetupLogResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ExecInstanceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CloudId     string            `protobuf:"bytes,1,opt,name=cloud_id,json=cloudId,proto3" json:"cloud_id,omitempty"`
+	TargetHosts []string          `protobuf:"bytes,2,rep,name=target_hosts,json=targetHosts,proto3" json:"target_hosts,omitempty"`
+	Command     []string          `protobuf:"bytes,3,rep,name=command,proto3" json:"command,omitempty"`
+	Environment map[string]string `protobuf:"bytes,4,rep,name=environment,proto3" json:"environment,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// timeout of command in seconds. 0 is unlimited.
+	TimeoutSec int32 `protobuf:"varint,5,opt,name=timeout_sec,json=timeoutSec,proto3" json:"timeout_sec,omitempty"`
+}
+
+func (x *ExecInstanceRequest) Reset() {
+	*x = ExecInstanceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecInstanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecInstanceRequest) ProtoMessage() {}
+
+func (x *ExecInstanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecInstanceRequest.ProtoReflect.Descriptor instead.
+func (*ExecInstanceRequest) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ExecInstanceRequest) GetCloudId() string {
+	if x != nil {
+		return x.CloudId
+	}
+	return ""
+}
+
+func (x *ExecInstanceRequest) GetTargetHosts() []string {
+	if x != nil {
+		return x.TargetHosts
+	}
+	return nil
+}
+
+func (x *ExecInstanceRequest) GetCommand() []string {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *ExecInstanceRequest) GetEnvironment() map[string]string {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+func (x *ExecInstanceRequest) GetTimeoutSec() int32 {
+	if x != nil {
+		return x.TimeoutSec
+	}
+	return 0
+}
+
+type ExecInstanceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Output:
+	//	*ExecInstanceResponse_Stdout
+	//	*ExecInstanceResponse_Stderr
+	//	*ExecInstanceResponse_ExitCode
+	Output isExecInstanceResponse_Output `protobuf_oneof:"output"`
+}
+
+func (x *ExecInstanceResponse) Reset() {
+	*x = ExecInstanceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecInstanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecInstanceResponse) ProtoMessage() {}
+
+func (x *ExecInstanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecInstanceResponse.ProtoReflect.Descriptor instead.
+func (*ExecInstanceResponse) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{23}
+}
+
+func (m *ExecInstanceResponse) GetOutput() isExecInstanceResponse_Output {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
+func (x *ExecInstanceResponse) GetStdout() []byte {
+	if x, ok := x.GetOutput().(*ExecInstanceResponse_Stdout); ok {
+		return x.Stdout
+	}
+	return nil
+}
+
+func (x *ExecInstanceResponse) GetStderr() []byte {
+	if x, ok := x.GetOutput().(*ExecInstanceResponse_Stderr); ok {
+		return x.Stderr
+	}
+	return nil
+}
+
+func (x *ExecInstanceResponse) GetExitCode() int32 {
+	if x, ok := x.GetOutput().(*ExecInstanceResponse_ExitCode); ok {
+		return x.ExitCode
+	}
+	return 0
+}
+
+type isExecInstanceResponse_Output interface {
+	isExecInstanceResponse_Output()
+}
+
+type ExecInstanceResponse_Stdout struct {
+	// chunk of stdout
+	Stdout []byte `protobuf:"bytes,1,opt,name=stdout,proto3,oneof"`
+}
+
+type ExecInstanceResponse_Stderr struct {
+	// chunk of stderr
+	Stderr []byte `protobuf:"bytes,2,opt,name=stderr,proto3,oneof"`
+}
+
+type ExecInstanceResponse_ExitCode struct {
+	// exit code of command, sent as the last message
+	ExitCode int32 `protobuf:"varint,3,opt,name=exit_code,json=exitCode,proto3,oneof"`
+}
+
+func (*ExecInstanceResponse_Stdout) isExecInstanceResponse_Output() {}
+
+func (*ExecInstanceResponse_Stderr) isExecInstanceResponse_Output() {}
+
+func (*ExecInstanceResponse_ExitCode) isExecInstanceResponse_Output() {}
+
+type PullFileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CloudId     string   `protobuf:"bytes,1,opt,name=cloud_id,json=cloudId,proto3" json:"cloud_id,omitempty"`
+	TargetHosts []string `protobuf:"bytes,2,rep,name=target_hosts,json=targetHosts,proto3" json:"target_hosts,omitempty"`
+	// absolute path of file in instance
+	Path string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *PullFileRequest) Reset() {
+	*x = PullFileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PullFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullFileRequest) ProtoMessage() {}
+
+func (x *PullFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullFileRequest.ProtoReflect.Descriptor instead.
+func (*PullFileRequest) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PullFileRequest) GetCloudId() string {
+	if x != nil {
+		return x.CloudId
+	}
+	return ""
+}
+
+func (x *PullFileRequest) GetTargetHosts() []string {
+	if x != nil {
+		return x.TargetHosts
+	}
+	return nil
+}
+
+func (x *PullFileRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type PullFileResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// chunk of file content
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// names of entries if path is directory, sent as the only message
+	Entries []string `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *PullFileResponse) Reset() {
+	*x = PullFileResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PullFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullFileResponse) ProtoMessage() {}
+
+func (x *PullFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullFileResponse.ProtoReflect.Descriptor instead.
+func (*PullFileResponse) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PullFileResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *PullFileResponse) GetEntries() []string {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type ListQuarantinedInstancesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// all hosts are searched if empty
+	TargetHosts []string `protobuf:"bytes,1,rep,name=target_hosts,json=targetHosts,proto3" json:"target_hosts,omitempty"`
+}
+
+func (x *ListQuarantinedInstancesRequest) Reset() {
+	*x = ListQuarantinedInstancesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListQuarantinedInstancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuarantinedInstancesRequest) ProtoMessage() {}
+
+func (x *ListQuarantinedInstancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuarantinedInstancesRequest.ProtoReflect.Descriptor instead.
+func (*ListQuarantinedInstancesRequest) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListQuarantinedInstancesRequest) GetTargetHosts() []string {
+	if x != nil {
+		return x.TargetHosts
+	}
+	return nil
+}
+
+type QuarantinedInstance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Host          string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	InstanceName  string                 `protobuf:"bytes,2,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	RunnerName    string                 `protobuf:"bytes,3,opt,name=runner_name,json=runnerName,proto3" json:"runner_name,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	QuarantinedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=quarantined_at,json=quarantinedAt,proto3" json:"quarantined_at,omitempty"`
+	// status of instance in LXD (e.g. Frozen)
+	Status string `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *QuarantinedInstance) Reset() {
+	*x = QuarantinedInstance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuarantinedInstance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantinedInstance) ProtoMessage() {}
+
+func (x *QuarantinedInstance) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantinedInstance.ProtoReflect.Descriptor instead.
+func (*QuarantinedInstance) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *QuarantinedInstance) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *QuarantinedInstance) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *QuarantinedInstance) GetRunnerName() string {
+	if x != nil {
+		return x.RunnerName
+	}
+	return ""
+}
+
+func (x *QuarantinedInstance) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *QuarantinedInstance) GetQuarantinedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.QuarantinedAt
+	}
+	return nil
+}
+
+func (x *QuarantinedInstance) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListQuarantinedInstancesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Instances []*QuarantinedInstance `protobuf:"bytes,1,rep,name=instances,proto3" json:"instances,omitempty"`
+}
+
+func (x *ListQuarantinedInstancesResponse) Reset() {
+	*x = ListQuarantinedInstancesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListQuarantinedInstancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuarantinedInstancesResponse) ProtoMessage() {}
+
+func (x *ListQuarantinedInstancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuarantinedInstancesResponse.ProtoReflect.Descriptor instead.
+func (*ListQuarantinedInstancesResponse) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListQuarantinedInstancesResponse) GetInstances() []*QuarantinedInstance {
+	if x != nil {
+		return x.Instances
+	}
+	return nil
+}
+
+type ReleaseQuarantinedInstanceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CloudId     string   `protobuf:"bytes,1,opt,name=cloud_id,json=cloudId,proto3" json:"cloud_id,omitempty"`
+	TargetHosts []string `protobuf:"bytes,2,rep,name=target_hosts,json=targetHosts,proto3" json:"target_hosts,omitempty"`
+}
+
+func (x *ReleaseQuarantinedInstanceRequest) Reset() {
+	*x = ReleaseQuarantinedInstanceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReleaseQuarantinedInstanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseQuarantinedInstanceRequest) ProtoMessage() {}
+
+func (x *ReleaseQuarantinedInstanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseQuarantinedInstanceRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseQuarantinedInstanceRequest) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ReleaseQuarantinedInstanceRequest) GetCloudId() string {
+	if x != nil {
+		return x.CloudId
+	}
+	return ""
+}
+
+func (x *ReleaseQuarantinedInstanceRequest) GetTargetHosts() []string {
+	if x != nil {
+		return x.TargetHosts
+	}
+	return nil
+}
+
+type ReleaseQuarantinedInstanceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ReleaseQuarantinedInstanceResponse) Reset() {
+	*x = ReleaseQuarantinedInstanceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReleaseQuarantinedInstanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseQuarantinedInstanceResponse) ProtoMessage() {}
+
+func (x *ReleaseQuarantinedInstanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseQuarantinedInstanceResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseQuarantinedInstanceResponse) Descriptor() ([]byte, []int) {
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP(), []int{30}
+}
+
+var File_shoeslxdmulti_shoes_lxd_multi_proto protoreflect.FileDescriptor
+
+var file_shoeslxdmulti_shoes_lxd_multi_proto_rawDesc = []byte{
+	0x0a, 0x23, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2f,
+	0x73, 0x68, 0x6f, 0x65, 0x73, 0x2d, 0x6c, 0x78, 0x64, 0x2d, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x1a, 0x16, 0x77, 0x68, 0x79, 0x77, 0x61, 0x69, 0x74, 0x61, 0x2f, 0x6d,
+	0x79, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xcb, 0x03,
+	0x0a, 0x12, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x75, 0x6e, 0x6e, 0x65,
+	0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x74, 0x75, 0x70, 0x5f, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x65, 0x74,
+	0x75, 0x70, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x43, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1e, 0x2e, 0x77, 0x68, 0x79, 0x77, 0x61, 0x69, 0x74, 0x61, 0x2e, 0x6d, 0x79, 0x73, 0x68, 0x6f,
+	0x65, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x0c, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f,
+	0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0b, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x5f, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x02, 0x18,
+	0x01, 0x52, 0x0a, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x1d, 0x0a,
+	0x0a, 0x6f, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x6f, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x67, 0x0a, 0x12,
+	0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x5f, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65,
+	0x6e, 0x74, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73,
+	0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x11, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f,
+	0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x44, 0x0a, 0x16, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x45,
+	0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xb3, 0x01, 0x0a, 0x13,
+	0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x64, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x43, 0x0a, 0x0d,
+	0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x77, 0x68, 0x79, 0x77, 0x61, 0x69, 0x74, 0x61, 0x2e, 0x6d,
+	0x79, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70,
+	0x65, 0x22, 0x55, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f,
+	0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x22, 0x18, 0x0a, 0x16, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x5f, 0x0a, 0x19, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x79, 0x52, 0x75,
+	0x6e, 0x6e, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1f, 0x0a, 0x0b, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x21, 0x0a, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x6f,
+	0x73, 0x74, 0x73, 0x22, 0x37, 0x0a, 0x1a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x79, 0x52,
+	0x75, 0x6e, 0x6e, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x64, 0x22, 0x3a, 0x0a, 0x0a,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x56, 0x0a, 0x13, 0x41, 0x64, 0x64, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x3f, 0x0a, 0x09, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c,
+	0x74, 0x69, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x09, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73,
+	0x22, 0xa6, 0x01, 0x0a, 0x12, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x75, 0x6e, 0x6e, 0x65,
+	0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x75,
+	0x6e, 0x6e, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x3e, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x73, 0x68, 0x6f,
+	0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x2f, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c,
+	0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x53, 0x0a, 0x14, 0x41, 0x64, 0x64,
+	0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x3b, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c,
+	0x74, 0x69, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x5c,
+	0x0a, 0x16, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x42, 0x0a, 0x09, 0x69, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x73, 0x68,
+	0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x52, 0x09, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x22, 0x63, 0x0a, 0x15,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x64,
+	0x12, 0x2f, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x59, 0x0a, 0x17, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x07,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e,
+	0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x27, 0x0a, 0x11,
+	0x43, 0x6f, 0x72, 0x64, 0x6f, 0x6e, 0x48, 0x6f, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x68, 0x6f, 0x73, 0x74, 0x22, 0x14, 0x0a, 0x12, 0x43, 0x6f, 0x72, 0x64, 0x6f, 0x6e, 0x48,
+	0x6f, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x29, 0x0a, 0x13, 0x55,
+	0x6e, 0x63, 0x6f, 0x72, 0x64, 0x6f, 0x6e, 0x48, 0x6f, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x22, 0x16, 0x0a, 0x14, 0x55, 0x6e, 0x63, 0x6f, 0x72, 0x64,
+	0x6f, 0x6e, 0x48, 0x6f, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xaf,
+	0x01, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x75, 0x6e, 0x6e,
+	0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x22, 0xc7, 0x03, 0x0a, 0x0c, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1f, 0x0a, 0x0b, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x68, 0x6f, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x5f, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x69, 0x6d, 0x61, 0x67, 0x65, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x3d, 0x0a, 0x0c, 0x61, 0x6c,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x61, 0x6c,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x3b, 0x0a, 0x0b, 0x66, 0x69, 0x6e,
+	0x69, 0x73, 0x68, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x66, 0x69, 0x6e, 0x69,
+	0x73, 0x68, 0x65, 0x64, 0x41, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d,
+	0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x4c, 0x0a, 0x13, 0x4c, 0x69,
+	0x73, 0x74, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x35, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c,
+	0x74, 0x69, 0x2e, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0xc0, 0x01, 0x0a, 0x12, 0x47, 0x65, 0x74,
+	0x53, 0x65, 0x74, 0x75, 0x70, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x35, 0x0a,
+	0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e,
+	0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x53, 0x65,
+	0x74, 0x75, 0x70, 0x4c, 0x6f, 0x67, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x06, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x12, 0x1d, 0x0a, 0x0a,
+	0x74, 0x61, 0x69, 0x6c, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x09, 0x74, 0x61, 0x69, 0x6c, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x22, 0x29, 0x0a, 0x13, 0x47,
+	0x65, 0x74, 0x53, 0x65, 0x74, 0x75, 0x70, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0xa5, 0x02, 0x0a, 0x13, 0x45, 0x78, 0x65, 0x63, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19,
+	0x0a, 0x08, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x55, 0x0a, 0x0b, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f,
+	0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x73, 0x68,
+	0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x45, 0x78, 0x65, 0x63,
+	0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e,
+	0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0b, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a,
+	0x0b, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x1a, 0x3e,
+	0x0a, 0x10, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x73,
+	0x0a, 0x14, 0x45, 0x78, 0x65, 0x63, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74,
+	0x12, 0x18, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x12, 0x1d, 0x0a, 0x09, 0x65, 0x78,
+	0x69, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52,
+	0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x22, 0x63, 0x0a, 0x0f, 0x50, 0x75, 0x6c, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x49,
+	0x64, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x68, 0x6f, 0x73, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48,
+	0x6f, 0x73, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x40, 0x0a, 0x10, 0x50, 0x75, 0x6c, 0x6c,
+	0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x44, 0x0a, 0x1f, 0x4c, 0x69,
+	0x73, 0x74, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x49, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a,
+	0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73,
+	0x22, 0xe2, 0x01, 0x0a, 0x13, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64,
+	0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x41, 0x0a, 0x0e, 0x71, 0x75,
+	0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0d,
+	0x71, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x41, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x64, 0x0a, 0x20, 0x4c, 0x69, 0x73, 0x74, 0x51, 0x75, 0x61,
+	0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x73,
+	0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x51, 0x75, 0x61,
+	0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x52, 0x09, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x22, 0x61, 0x0a, 0x21, 0x52,
+	0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65,
+	0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x19, 0x0a, 0x08, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x22, 0x24,
+	0x0a, 0x22, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74,
+	0x69, 0x6e, 0x65, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x2a, 0x4c, 0x0a, 0x0e, 0x53, 0x65, 0x74, 0x75, 0x70, 0x4c, 0x6f, 0x67,
+	0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x53, 0x45, 0x54, 0x55, 0x50, 0x5f,
+	0x4c, 0x4f, 0x47, 0x5f, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x4a, 0x4f, 0x55, 0x52, 0x4e,
+	0x41, 0x4c, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x53, 0x45, 0x54, 0x55, 0x50, 0x5f, 0x4c, 0x4f,
+	0x47, 0x5f, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x53, 0x4f, 0x4c, 0x45,
+	0x10, 0x01, 0x32, 0x89, 0x0a, 0x0a, 0x0d, 0x53, 0x68, 0x6f, 0x65, 0x73, 0x4c, 0x58, 0x44, 0x4d,
+	0x75, 0x6c, 0x74, 0x69, 0x12, 0x56, 0x0a, 0x0b, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x12, 0x21, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75,
+	0x6c, 0x74, 0x69, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78,
+	0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5f, 0x0a, 0x0e,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x24,
+	0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6b, 0x0a,
+	0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x79, 0x52, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x28, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75,
+	0x6c, 0x74, 0x69, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x79, 0x52, 0x75, 0x6e, 0x6e,
+	0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e,
+	0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x42, 0x79, 0x52, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x59, 0x0a, 0x0c, 0x41, 0x64,
+	0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x22, 0x2e, 0x73, 0x68, 0x6f,
+	0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23,
+	0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x41,
+	0x64, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x62, 0x0a, 0x0f, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x25, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73,
+	0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x26, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x53, 0x0a, 0x0a, 0x43, 0x6f, 0x72,
+	0x64, 0x6f, 0x6e, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x20, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c,
+	0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x43, 0x6f, 0x72, 0x64, 0x6f, 0x6e, 0x48, 0x6f,
+	0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x73, 0x68, 0x6f, 0x65,
+	0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x43, 0x6f, 0x72, 0x64, 0x6f, 0x6e,
+	0x48, 0x6f, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x59,
+	0x0a, 0x0c, 0x55, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x6f, 0x6e, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x22,
+	0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x55,
+	0x6e, 0x63, 0x6f, 0x72, 0x64, 0x6f, 0x6e, 0x48, 0x6f, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x23, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c,
+	0x74, 0x69, 0x2e, 0x55, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x6f, 0x6e, 0x48, 0x6f, 0x73, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x56, 0x0a, 0x0b, 0x4c, 0x69, 0x73,
+	0x74, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x12, 0x21, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73,
+	0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x6f, 0x75,
+	0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x73, 0x68,
+	0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x58, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x53, 0x65, 0x74, 0x75, 0x70, 0x4c, 0x6f, 0x67,
+	0x12, 0x21, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69,
+	0x2e, 0x47, 0x65, 0x74, 0x53, 0x65, 0x74, 0x75, 0x70, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75,
+	0x6c, 0x74, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x65, 0x74, 0x75, 0x70, 0x4c, 0x6f, 0x67, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x5b, 0x0a, 0x0c, 0x45,
+	0x78, 0x65, 0x63, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x22, 0x2e, 0x73, 0x68,
+	0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x45, 0x78, 0x65, 0x63,
+	0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x23, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e,
+	0x45, 0x78, 0x65, 0x63, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4f, 0x0a, 0x08, 0x50, 0x75, 0x6c, 0x6c,
+	0x46, 0x69, 0x6c, 0x65, 0x12, 0x1e, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x2e, 0x50, 0x75, 0x6c, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x2e, 0x50, 0x75, 0x6c, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x7d, 0x0a, 0x18, 0x4c, 0x69, 0x73,
+	0x74, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x49, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x2e, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e,
+	0x74, 0x69, 0x6e, 0x65, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2f, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e,
+	0x74, 0x69, 0x6e, 0x65, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x83, 0x01, 0x0a, 0x1a, 0x52, 0x65, 0x6c,
+	0x65, 0x61, 0x73, 0x65, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x30, 0x2e, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x6c,
+	0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x51,
+	0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x31, 0x2e, 0x73, 0x68, 0x6f, 0x65,
+	0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2e, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73,
+	0x65, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x49, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x3c,
+	0x5a, 0x3a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x68, 0x79,
+	0x77, 0x61, 0x69, 0x74, 0x61, 0x2f, 0x73, 0x68, 0x6f, 0x65, 0x73, 0x2d, 0x6c, 0x78, 0x64, 0x2d,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x6f, 0x2f, 0x73,
+	0x68, 0x6f, 0x65, 0x73, 0x6c, 0x78, 0x64, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescOnce sync.Once
+	file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescData = file_shoeslxdmulti_shoes_lxd_multi_proto_rawDesc
+)
+
+func file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescGZIP() []byte {
+	file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescOnce.Do(func() {
+		file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescData = protoimpl.X.CompressGZIP(file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescData)
+	})
+	return file_shoeslxdmulti_shoes_lxd_multi_proto_rawDescData
+}
+
+var file_shoeslxdmulti_shoes_lxd_multi_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
+var file_shoeslxdmulti_shoes_lxd_multi_proto_goTypes = []interface{}{
+	(SetupLogSource)(0),                        // 0: shoeslxdmulti.SetupLogSource
+	(*AddInstanceRequest)(nil),                 // 1: shoeslxdmulti.AddInstanceRequest
+	(*AddInstanceResponse)(nil),                // 2: shoeslxdmulti.AddInstanceResponse
+	(*DeleteInstanceRequest)(nil),              // 3: shoeslxdmulti.DeleteInstanceRequest
+	(*DeleteInstanceResponse)(nil),             // 4: shoeslxdmulti.DeleteInstanceResponse
+	(*DeleteByRunnerNameRequest)(nil),          // 5: shoeslxdmulti.DeleteByRunnerNameRequest
+	(*DeleteByRunnerNameResponse)(nil),         // 6: shoeslxdmulti.DeleteByRunnerNameResponse
+	(*BatchError)(nil),                         // 7: shoeslxdmulti.BatchError
+	(*AddInstancesRequest)(nil),                // 8: shoeslxdmulti.AddInstancesRequest
+	(*AddInstancesResult)(nil),                 // 9: shoeslxdmulti.AddInstancesResult
+	(*AddInstancesResponse)(nil),               // 10: shoeslxdmulti.AddInstancesResponse
+	(*DeleteInstancesRequest)(nil),             // 11: shoeslxdmulti.DeleteInstancesRequest
+	(*DeleteInstancesResult)(nil),              // 12: shoeslxdmulti.DeleteInstancesResult
+	(*DeleteInstancesResponse)(nil),            // 13: shoeslxdmulti.DeleteInstancesResponse
+	(*CordonHostRequest)(nil),                  // 14: shoeslxdmulti.CordonHostRequest
+	(*CordonHostResponse)(nil),                 // 15: shoeslxdmulti.CordonHostResponse
+	(*UncordonHostRequest)(nil),                // 16: shoeslxdmulti.UncordonHostRequest
+	(*UncordonHostResponse)(nil),               // 17: shoeslxdmulti.UncordonHostResponse
+	(*ListJournalRequest)(nil),                 // 18: shoeslxdmulti.ListJournalRequest
+	(*JournalEntry)(nil),                       // 19: shoeslxdmulti.JournalEntry
+	(*ListJournalResponse)(nil),                // 20: shoeslxdmulti.ListJournalResponse
+	(*GetSetupLogRequest)(nil),                 // 21: shoeslxdmulti.GetSetupLogRequest
+	(*GetSetupLogResponse)(nil),                // 22: shoeslxdmulti.GetSetupLogResponse
+	(*ExecInstanceRequest)(nil),                // 23: shoeslxdmulti.ExecInstanceRequest
+	(*ExecInstanceResponse)(nil),               // 24: shoeslxdmulti.ExecInstanceResponse
+	(*PullFileRequest)(nil),                    // 25: shoeslxdmulti.PullFileRequest
+	(*PullFileResponse)(nil),                   // 26: shoeslxdmulti.PullFileResponse
+	(*ListQuarantinedInstancesRequest)(nil),    // 27: shoeslxdmulti.ListQuarantinedInstancesRequest
+	(*QuarantinedInstance)(nil),                // 28: shoeslxdmulti.QuarantinedInstance
+	(*ListQuarantinedInstancesResponse)(nil),   // 29: shoeslxdmulti.ListQuarantinedInstancesResponse
+	(*ReleaseQuarantinedInstanceRequest)(nil),  // 30: shoeslxdmulti.ReleaseQuarantinedInstanceRequest
+	(*ReleaseQuarantinedInstanceResponse)(nil), // 31: shoeslxdmulti.ReleaseQuarantinedInstanceResponse
+	nil,                           // 32: shoeslxdmulti.AddInstanceRequest.SecretEnvironmentEntry
+	nil,                           // 33: shoeslxdmulti.ExecInstanceRequest.EnvironmentEntry
+	(proto_go.ResourceType)(0),    // 34: whywaita.myshoes.ResourceType
+	(*timestamppb.Timestamp)(nil), // 35: google.protobuf.Timestamp
+}
+var file_shoeslxdmulti_shoes_lxd_multi_proto_depIdxs = []int32{
+	34, // 0: shoeslxdmulti.AddInstanceRequest.resource_type:type_name -> whywaita.myshoes.ResourceType
+	32, // 1: shoeslxdmulti.AddInstanceRequest.secret_environment:type_name -> shoeslxdmulti.AddInstanceRequest.SecretEnvironmentEntry
+	34, // 2: shoeslxdmulti.AddInstanceResponse.resource_type:type_name -> whywaita.myshoes.ResourceType
+	1,  // 3: shoeslxdmulti.AddInstancesRequest.instances:type_name -> shoeslxdmulti.AddInstanceRequest
+	2,  // 4: shoeslxdmulti.AddInstancesResult.instance:type_name -> shoeslxdmulti.AddInstanceResponse
+	7,  // 5: shoeslxdmulti.AddInstancesResult.error:type_name -> shoeslxdmulti.BatchError
+	9,  // 6: shoeslxdmulti.AddInstancesResponse.results:type_name -> shoeslxdmulti.AddInstancesResult
+	3,  // 7: shoeslxdmulti.DeleteInstancesRequest.instances:type_name -> shoeslxdmulti.DeleteInstanceRequest
+	7,  // 8: shoeslxdmulti.DeleteInstancesResult.error:type_name -> shoeslxdmulti.BatchError
+	12, // 9: shoeslxdmulti.DeleteInstancesResponse.results:type_name -> shoeslxdmulti.DeleteInstancesResult
+	35, // 10: shoeslxdmulti.ListJournalRequest.since:type_name -> google.protobuf.Timestamp
+	35, // 11: shoeslxdmulti.ListJournalRequest.until:type_name -> google.protobuf.Timestamp
+	35, // 12: shoeslxdmulti.JournalEntry.started_at:type_name -> google.protobuf.Timestamp
+	35, // 13: shoeslxdmulti.JournalEntry.allocated_at:type_name -> google.protobuf.Timestamp
+	35, // 14: shoeslxdmulti.JournalEntry.finished_at:type_name -> google.protobuf.Timestamp
+	19, // 15: shoeslxdmulti.ListJournalResponse.entries:type_name -> shoeslxdmulti.JournalEntry
+	0,  // 16: shoeslxdmulti.GetSetupLogRequest.source:type_name -> shoeslxdmulti.SetupLogSource
+	33, // 17: shoeslxdmulti.ExecInstanceRequest.environment:type_name -> shoeslxdmulti.ExecInstanceRequest.EnvironmentEntry
+	35, // 18: shoeslxdmulti.QuarantinedInstance.quarantined_at:type_name -> google.protobuf.Timestamp
+	28, // 19: shoeslxdmulti.ListQuarantinedInstancesResponse.instances:type_name -> shoeslxdmulti.QuarantinedInstance
+	1,  // 20: shoeslxdmulti.ShoesLXDMulti.AddInstance:input_type -> shoeslxdmulti.AddInstanceRequest
+	3,  // 21: shoeslxdmulti.ShoesLXDMulti.DeleteInstance:input_type -> shoeslxdmulti.DeleteInstanceRequest
+	5,  // 22: shoeslxdmulti.ShoesLXDMulti.DeleteByRunnerName:input_type -> shoeslxdmulti.DeleteByRunnerNameRequest
+	8,  // 23: shoeslxdmulti.ShoesLXDMulti.AddInstances:input_type -> shoeslxdmulti.AddInstancesRequest
+	11, // 24: shoeslxdmulti.ShoesLXDMulti.DeleteInstances:input_type -> shoeslxdmulti.DeleteInstancesRequest
+	14, // 25: shoeslxdmulti.ShoesLXDMulti.CordonHost:input_type -> shoeslxdmulti.CordonHostRequest
+	16, // 26: shoeslxdmulti.ShoesLXDMulti.UncordonHost:input_type -> shoeslxdmulti.UncordonHostRequest
+	18, // 27: shoeslxdmulti.ShoesLXDMulti.ListJournal:input_type -> shoeslxdmulti.ListJournalRequest
+	21, // 28: shoeslxdmulti.ShoesLXDMulti.GetSetupLog:input_type -> shoeslxdmulti.GetSetupLogRequest
+	23, // 29: shoeslxdmulti.ShoesLXDMulti.ExecInstance:input_type -> shoeslxdmulti.ExecInstanceRequest
+	25, // 30: shoeslxdmulti.ShoesLXDMulti.PullFile:input_type -> shoeslxdmulti.PullFileRequest
+	27, // 31: shoeslxdmulti.ShoesLXDMulti.ListQuarantinedInstances:input_type -> shoeslxdmulti.ListQuarantinedInstancesRequest
+	30, // 32: shoeslxdmulti.ShoesLXDMulti.ReleaseQuarantinedInstance:input_type -> shoeslxdmulti.ReleaseQuarantinedInstanceRequest
+	2,  // 33: shoeslxdmulti.ShoesLXDMulti.AddInstance:output_type -> shoeslxdmulti.AddInstanceResponse
+	4,  // 34: shoeslxdmulti.ShoesLXDMulti.DeleteInstance:output_type -> shoeslxdmulti.DeleteInstanceResponse
+	6,  // 35: shoeslxdmulti.ShoesLXDMulti.DeleteByRunnerName:output_type -> shoeslxdmulti.DeleteByRunnerNameResponse
+	10, // 36: shoeslxdmulti.ShoesLXDMulti.AddInstances:output_type -> shoeslxdmulti.AddInstancesResponse
+	13, // 37: shoeslxdmulti.ShoesLXDMulti.DeleteInstances:output_type -> shoeslxdmulti.DeleteInstancesResponse
+	15, // 38: shoeslxdmulti.ShoesLXDMulti.CordonHost:output_type -> shoeslxdmulti.CordonHostResponse
+	17, // 39: shoeslxdmulti.ShoesLXDMulti.UncordonHost:output_type -> shoeslxdmulti.UncordonHostResponse
+	20, // 40: shoeslxdmulti.ShoesLXDMulti.ListJournal:output_type -> shoeslxdmulti.ListJournalResponse
+	22, // 41: shoeslxdmulti.ShoesLXDMulti.GetSetupLog:output_type -> shoeslxdmulti.GetSetupLogResponse
+	24, // 42: shoeslxdmulti.ShoesLXDMulti.ExecInstance:output_type -> shoeslxdmulti.ExecInstanceResponse
+	26, // 43: shoeslxdmulti.ShoesLXDMulti.PullFile:output_type -> shoeslxdmulti.PullFileResponse
+	29, // 44: shoeslxdmulti.ShoesLXDMulti.ListQuarantinedInstances:output_type -> shoeslxdmulti.ListQuarantinedInstancesResponse
+	31, // 45: shoeslxdmulti.ShoesLXDMulti.ReleaseQuarantinedInstance:output_type -> shoeslxdmulti.ReleaseQuarantinedInstanceResponse
+	33, // [33:46] is the sub-list for method output_type
+	20, // [20:33] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_shoeslxdmulti_shoes_lxd_multi_proto_init() }
+func file_shoeslxdmulti_shoes_lxd_multi_proto_init() {
+	if File_shoeslxdmulti_shoes_lxd_multi_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddInstanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddInstanceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteInstanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteInstanceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteByRunnerNameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteByRunnerNameResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddInstancesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddInstancesResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddInstancesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteInstancesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteInstancesResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteInstancesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CordonHostRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CordonHostResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UncordonHostRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UncordonHostResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListJournalRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JournalEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListJournalResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSetupLogRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSetupLogResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecInstanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecInstanceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PullFileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PullFileResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListQuarantinedInstancesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QuarantinedInstance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListQuarantinedInstancesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReleaseQuarantinedInstanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReleaseQuarantinedInstanceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes[23].OneofWrappers = []interface{}{
+		(*ExecInstanceResponse_Stdout)(nil),
+		(*ExecInstanceResponse_Stderr)(nil),
+		(*ExecInstanceResponse_ExitCode)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_shoeslxdmulti_shoes_lxd_multi_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      1,
+			NumMessages:   33,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_shoeslxdmulti_shoes_lxd_multi_proto_goTypes,
 		DependencyIndexes: file_shoeslxdmulti_shoes_lxd_multi_proto_depIdxs,
+		EnumInfos:         file_shoeslxdmulti_shoes_lxd_multi_proto_enumTypes,
 		MessageInfos:      file_shoeslxdmulti_shoes_lxd_multi_proto_msgTypes,
 	}.Build()
 	File_shoeslxdmulti_shoes_lxd_multi_proto = out.File
@@ -19,8 +19,19 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	ShoesLXDMulti_AddInstance_FullMethodName    = "/shoeslxdmulti.ShoesLXDMulti/AddInstance"
-	ShoesLXDMulti_DeleteInstance_FullMethodName = "/shoeslxdmulti.ShoesLXDMulti/DeleteInstance"
+	ShoesLXDMulti_AddInstance_FullMethodName                = "/shoeslxdmulti.ShoesLXDMulti/AddInstance"
+	ShoesLXDMulti_DeleteInstance_FullMethodName             = "/shoeslxdmulti.ShoesLXDMulti/DeleteInstance"
+	ShoesLXDMulti_DeleteByRunnerName_FullMethodName         = "/shoeslxdmulti.ShoesLXDMulti/DeleteByRunnerName"
+	ShoesLXDMulti_AddInstances_FullMethodName               = "/shoeslxdmulti.ShoesLXDMulti/AddInstances"
+	ShoesLXDMulti_DeleteInstances_FullMethodName            = "/shoeslxdmulti.ShoesLXDMulti/DeleteInstances"
+	ShoesLXDMulti_CordonHost_FullMethodName                 = "/shoeslxdmulti.ShoesLXDMulti/CordonHost"
+	ShoesLXDMulti_UncordonHost_FullMethodName               = "/shoeslxdmulti.ShoesLXDMulti/UncordonHost"
+	ShoesLXDMulti_ListJournal_FullMethodName                = "/shoeslxdmulti.ShoesLXDMulti/ListJournal"
+	ShoesLXDMulti_GetSetupLog_FullMethodName                = "/shoeslxdmulti.ShoesLXDMulti/GetSetupLog"
+	ShoesLXDMulti_ExecInstance_FullMethodName               = "/shoeslxdmulti.ShoesLXDMulti/ExecInstance"
+	ShoesLXDMulti_PullFile_FullMethodName                   = "/shoeslxdmulti.ShoesLXDMulti/PullFile"
+	ShoesLXDMulti_ListQuarantinedInstances_FullMethodName   = "/shoeslxdmulti.ShoesLXDMulti/ListQuarantinedInstances"
+	ShoesLXDMulti_ReleaseQuarantinedInstance_FullMethodName = "/shoeslxdmulti.ShoesLXDMulti/ReleaseQuarantinedInstance"
 )
 
 // ShoesLXDMultiClient is the client API for ShoesLXDMulti service.
@@ -29,6 +40,27 @@ const (
 type ShoesLXDMultiClient interface {
 	AddInstance(ctx context.Context, in *AddInstanceRequest, opts ...grpc.CallOption) (*AddInstanceResponse, error)
 	DeleteInstance(ctx context.Context, in *DeleteInstanceRequest, opts ...grpc.CallOption) (*DeleteInstanceResponse, error)
+	// DeleteByRunnerName delete instance that is allocated to the runner, for the caller that does not know cloud_id
+	DeleteByRunnerName(ctx context.Context, in *DeleteByRunnerNameRequest, opts ...grpc.CallOption) (*DeleteByRunnerNameResponse, error)
+	// AddInstances add instances in one request. Target hosts are scanned once for instances that have same target hosts.
+	AddInstances(ctx context.Context, in *AddInstancesRequest, opts ...grpc.CallOption) (*AddInstancesResponse, error)
+	// DeleteInstances delete instances in one request
+	DeleteInstances(ctx context.Context, in *DeleteInstancesRequest, opts ...grpc.CallOption) (*DeleteInstancesResponse, error)
+	// CordonHost mark host as unschedulable, new instances are not allocated in the host
+	CordonHost(ctx context.Context, in *CordonHostRequest, opts ...grpc.CallOption) (*CordonHostResponse, error)
+	UncordonHost(ctx context.Context, in *UncordonHostRequest, opts ...grpc.CallOption) (*UncordonHostResponse, error)
+	// ListJournal return journal of allocation and deletion
+	ListJournal(ctx context.Context, in *ListJournalRequest, opts ...grpc.CallOption) (*ListJournalResponse, error)
+	// GetSetupLog return log of setup script (myshoes-setup unit) in instance
+	GetSetupLog(ctx context.Context, in *GetSetupLogRequest, opts ...grpc.CallOption) (ShoesLXDMulti_GetSetupLogClient, error)
+	// ExecInstance run command in instance, and return its stdout and stderr. It requires admin scope.
+	ExecInstance(ctx context.Context, in *ExecInstanceRequest, opts ...grpc.CallOption) (ShoesLXDMulti_ExecInstanceClient, error)
+	// PullFile return file in instance. It requires admin scope.
+	PullFile(ctx context.Context, in *PullFileRequest, opts ...grpc.CallOption) (ShoesLXDMulti_PullFileClient, error)
+	// ListQuarantinedInstances return instances that are quarantined by failure of setup. It requires admin scope.
+	ListQuarantinedInstances(ctx context.Context, in *ListQuarantinedInstancesRequest, opts ...grpc.CallOption) (*ListQuarantinedInstancesResponse, error)
+	// ReleaseQuarantinedInstance delete quarantined instance. It requires admin scope.
+	ReleaseQuarantinedInstance(ctx context.Context, in *ReleaseQuarantinedInstanceRequest, opts ...grpc.CallOption) (*ReleaseQuarantinedInstanceResponse, error)
 }
 
 type shoesLXDMultiClient struct {
@@ -57,12 +89,201 @@ func (c *shoesLXDMultiClient) DeleteInstance(ctx context.Context, in *DeleteInst
 	return out, nil
 }
 
+func (c *shoesLXDMultiClient) DeleteByRunnerName(ctx context.Context, in *DeleteByRunnerNameRequest, opts ...grpc.CallOption) (*DeleteByRunnerNameResponse, error) {
+	out := new(DeleteByRunnerNameResponse)
+	err := c.cc.Invoke(ctx, ShoesLXDMulti_DeleteByRunnerName_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoesLXDMultiClient) AddInstances(ctx context.Context, in *AddInstancesRequest, opts ...grpc.CallOption) (*AddInstancesResponse, error) {
+	out := new(AddInstancesResponse)
+	err := c.cc.Invoke(ctx, ShoesLXDMulti_AddInstances_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoesLXDMultiClient) DeleteInstances(ctx context.Context, in *DeleteInstancesRequest, opts ...grpc.CallOption) (*DeleteInstancesResponse, error) {
+	out := new(DeleteInstancesResponse)
+	err := c.cc.Invoke(ctx, ShoesLXDMulti_DeleteInstances_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoesLXDMultiClient) CordonHost(ctx context.Context, in *CordonHostRequest, opts ...grpc.CallOption) (*CordonHostResponse, error) {
+	out := new(CordonHostResponse)
+	err := c.cc.Invoke(ctx, ShoesLXDMulti_CordonHost_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoesLXDMultiClient) UncordonHost(ctx context.Context, in *UncordonHostRequest, opts ...grpc.CallOption) (*UncordonHostResponse, error) {
+	out := new(UncordonHostResponse)
+	err := c.cc.Invoke(ctx, ShoesLXDMulti_UncordonHost_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoesLXDMultiClient) ListJournal(ctx context.Context, in *ListJournalRequest, opts ...grpc.CallOption) (*ListJournalResponse, error) {
+	out := new(ListJournalResponse)
+	err := c.cc.Invoke(ctx, ShoesLXDMulti_ListJournal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoesLXDMultiClient) GetSetupLog(ctx context.Context, in *GetSetupLogRequest, opts ...grpc.CallOption) (ShoesLXDMulti_GetSetupLogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ShoesLXDMulti_ServiceDesc.Streams[0], ShoesLXDMulti_GetSetupLog_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shoesLXDMultiGetSetupLogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ShoesLXDMulti_GetSetupLogClient interface {
+	Recv() (*GetSetupLogResponse, error)
+	grpc.ClientStream
+}
+
+type shoesLXDMultiGetSetupLogClient struct {
+	grpc.ClientStream
+}
+
+func (x *shoesLXDMultiGetSetupLogClient) Recv() (*GetSetupLogResponse, error) {
+	m := new(GetSetupLogResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *shoesLXDMultiClient) ExecInstance(ctx context.Context, in *ExecInstanceRequest, opts ...grpc.CallOption) (ShoesLXDMulti_ExecInstanceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ShoesLXDMulti_ServiceDesc.Streams[1], ShoesLXDMulti_ExecInstance_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shoesLXDMultiExecInstanceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ShoesLXDMulti_ExecInstanceClient interface {
+	Recv() (*ExecInstanceResponse, error)
+	grpc.ClientStream
+}
+
+type shoesLXDMultiExecInstanceClient struct {
+	grpc.ClientStream
+}
+
+func (x *shoesLXDMultiExecInstanceClient) Recv() (*ExecInstanceResponse, error) {
+	m := new(ExecInstanceResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *shoesLXDMultiClient) PullFile(ctx context.Context, in *PullFileRequest, opts ...grpc.CallOption) (ShoesLXDMulti_PullFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ShoesLXDMulti_ServiceDesc.Streams[2], ShoesLXDMulti_PullFile_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shoesLXDMultiPullFileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ShoesLXDMulti_PullFileClient interface {
+	Recv() (*PullFileResponse, error)
+	grpc.ClientStream
+}
+
+type shoesLXDMultiPullFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *shoesLXDMultiPullFileClient) Recv() (*PullFileResponse, error) {
+	m := new(PullFileResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *shoesLXDMultiClient) ListQuarantinedInstances(ctx context.Context, in *ListQuarantinedInstancesRequest, opts ...grpc.CallOption) (*ListQuarantinedInstancesResponse, error) {
+	out := new(ListQuarantinedInstancesResponse)
+	err := c.cc.Invoke(ctx, ShoesLXDMulti_ListQuarantinedInstances_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoesLXDMultiClient) ReleaseQuarantinedInstance(ctx context.Context, in *ReleaseQuarantinedInstanceRequest, opts ...grpc.CallOption) (*ReleaseQuarantinedInstanceResponse, error) {
+	out := new(ReleaseQuarantinedInstanceResponse)
+	err := c.cc.Invoke(ctx, ShoesLXDMulti_ReleaseQuarantinedInstance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ShoesLXDMultiServer is the server API for ShoesLXDMulti service.
 // All implementations must embed UnimplementedShoesLXDMultiServer
 // for forward compatibility
 type ShoesLXDMultiServer interface {
 	AddInstance(context.Context, *AddInstanceRequest) (*AddInstanceResponse, error)
 	DeleteInstance(context.Context, *DeleteInstanceRequest) (*DeleteInstanceResponse, error)
+	// DeleteByRunnerName delete instance that is allocated to the runner, for the caller that does not know cloud_id
+	DeleteByRunnerName(context.Context, *DeleteByRunnerNameRequest) (*DeleteByRunnerNameResponse, error)
+	// AddInstances add instances in one request. Target hosts are scanned once for instances that have same target hosts.
+	AddInstances(context.Context, *AddInstancesRequest) (*AddInstancesResponse, error)
+	// DeleteInstances delete instances in one request
+	DeleteInstances(context.Context, *DeleteInstancesRequest) (*DeleteInstancesResponse, error)
+	// CordonHost mark host as unschedulable, new instances are not allocated in the host
+	CordonHost(context.Context, *CordonHostRequest) (*CordonHostResponse, error)
+	UncordonHost(context.Context, *UncordonHostRequest) (*UncordonHostResponse, error)
+	// ListJournal return journal of allocation and deletion
+	ListJournal(context.Context, *ListJournalRequest) (*ListJournalResponse, error)
+	// GetSetupLog return log of setup script (myshoes-setup unit) in instance
+	GetSetupLog(*GetSetupLogRequest, ShoesLXDMulti_GetSetupLogServer) error
+	// ExecInstance run command in instance, and return its stdout and stderr. It requires admin scope.
+	ExecInstance(*ExecInstanceRequest, ShoesLXDMulti_ExecInstanceServer) error
+	// PullFile return file in instance. It requires admin scope.
+	PullFile(*PullFileRequest, ShoesLXDMulti_PullFileServer) error
+	// ListQuarantinedInstances return instances that are quarantined by failure of setup. It requires admin scope.
+	ListQuarantinedInstances(context.Context, *ListQuarantinedInstancesRequest) (*ListQuarantinedInstancesResponse, error)
+	// ReleaseQuarantinedInstance delete quarantined instance. It requires admin scope.
+	ReleaseQuarantinedInstance(context.Context, *ReleaseQuarantinedInstanceRequest) (*ReleaseQuarantinedInstanceResponse, error)
 	mustEmbedUnimplementedShoesLXDMultiServer()
 }
 
@@ -76,6 +297,39 @@ func (UnimplementedShoesLXDMultiServer) AddInstance(context.Context, *AddInstanc
 func (UnimplementedShoesLXDMultiServer) DeleteInstance(context.Context, *DeleteInstanceRequest) (*DeleteInstanceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteInstance not implemented")
 }
+func (UnimplementedShoesLXDMultiServer) DeleteByRunnerName(context.Context, *DeleteByRunnerNameRequest) (*DeleteByRunnerNameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteByRunnerName not implemented")
+}
+func (UnimplementedShoesLXDMultiServer) AddInstances(context.Context, *AddInstancesRequest) (*AddInstancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddInstances not implemented")
+}
+func (UnimplementedShoesLXDMultiServer) DeleteInstances(context.Context, *DeleteInstancesRequest) (*DeleteInstancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteInstances not implemented")
+}
+func (UnimplementedShoesLXDMultiServer) CordonHost(context.Context, *CordonHostRequest) (*CordonHostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CordonHost not implemented")
+}
+func (UnimplementedShoesLXDMultiServer) UncordonHost(context.Context, *UncordonHostRequest) (*UncordonHostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UncordonHost not implemented")
+}
+func (UnimplementedShoesLXDMultiServer) ListJournal(context.Context, *ListJournalRequest) (*ListJournalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJournal not implemented")
+}
+func (UnimplementedShoesLXDMultiServer) GetSetupLog(*GetSetupLogRequest, ShoesLXDMulti_GetSetupLogServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetSetupLog not implemented")
+}
+func (UnimplementedShoesLXDMultiServer) ExecInstance(*ExecInstanceRequest, ShoesLXDMulti_ExecInstanceServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExecInstance not implemented")
+}
+func (UnimplementedShoesLXDMultiServer) PullFile(*PullFileRequest, ShoesLXDMulti_PullFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method PullFile not implemented")
+}
+func (UnimplementedShoesLXDMultiServer) ListQuarantinedInstances(context.Context, *ListQuarantinedInstancesRequest) (*ListQuarantinedInstancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQuarantinedInstances not implemented")
+}
+func (UnimplementedShoesLXDMultiServer) ReleaseQuarantinedInstance(context.Context, *ReleaseQuarantinedInstanceRequest) (*ReleaseQuarantinedInstanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseQuarantinedInstance not implemented")
+}
 func (UnimplementedShoesLXDMultiServer) mustEmbedUnimplementedShoesLXDMultiServer() {}
 
 // UnsafeShoesLXDMultiServer may be embedded to opt out of forward compatibility for this service.
@@ -125,6 +379,213 @@ func _ShoesLXDMulti_DeleteInstance_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShoesLXDMulti_DeleteByRunnerName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteByRunnerNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoesLXDMultiServer).DeleteByRunnerName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShoesLXDMulti_DeleteByRunnerName_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoesLXDMultiServer).DeleteByRunnerName(ctx, req.(*DeleteByRunnerNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoesLXDMulti_AddInstances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddInstancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoesLXDMultiServer).AddInstances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShoesLXDMulti_AddInstances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoesLXDMultiServer).AddInstances(ctx, req.(*AddInstancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoesLXDMulti_DeleteInstances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteInstancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoesLXDMultiServer).DeleteInstances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShoesLXDMulti_DeleteInstances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoesLXDMultiServer).DeleteInstances(ctx, req.(*DeleteInstancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoesLXDMulti_CordonHost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CordonHostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoesLXDMultiServer).CordonHost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShoesLXDMulti_CordonHost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoesLXDMultiServer).CordonHost(ctx, req.(*CordonHostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoesLXDMulti_UncordonHost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UncordonHostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoesLXDMultiServer).UncordonHost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShoesLXDMulti_UncordonHost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoesLXDMultiServer).UncordonHost(ctx, req.(*UncordonHostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoesLXDMulti_ListJournal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJournalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoesLXDMultiServer).ListJournal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShoesLXDMulti_ListJournal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoesLXDMultiServer).ListJournal(ctx, req.(*ListJournalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoesLXDMulti_GetSetupLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetSetupLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShoesLXDMultiServer).GetSetupLog(m, &shoesLXDMultiGetSetupLogServer{stream})
+}
+
+type ShoesLXDMulti_GetSetupLogServer interface {
+	Send(*GetSetupLogResponse) error
+	grpc.ServerStream
+}
+
+type shoesLXDMultiGetSetupLogServer struct {
+	grpc.ServerStream
+}
+
+func (x *shoesLXDMultiGetSetupLogServer) Send(m *GetSetupLogResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ShoesLXDMulti_ExecInstance_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecInstanceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShoesLXDMultiServer).ExecInstance(m, &shoesLXDMultiExecInstanceServer{stream})
+}
+
+type ShoesLXDMulti_ExecInstanceServer interface {
+	Send(*ExecInstanceResponse) error
+	grpc.ServerStream
+}
+
+type shoesLXDMultiExecInstanceServer struct {
+	grpc.ServerStream
+}
+
+func (x *shoesLXDMultiExecInstanceServer) Send(m *ExecInstanceResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ShoesLXDMulti_PullFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PullFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShoesLXDMultiServer).PullFile(m, &shoesLXDMultiPullFileServer{stream})
+}
+
+type ShoesLXDMulti_PullFileServer interface {
+	Send(*PullFileResponse) error
+	grpc.ServerStream
+}
+
+type shoesLXDMultiPullFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *shoesLXDMultiPullFileServer) Send(m *PullFileResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ShoesLXDMulti_ListQuarantinedInstances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQuarantinedInstancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoesLXDMultiServer).ListQuarantinedInstances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShoesLXDMulti_ListQuarantinedInstances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoesLXDMultiServer).ListQuarantinedInstances(ctx, req.(*ListQuarantinedInstancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoesLXDMulti_ReleaseQuarantinedInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseQuarantinedInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoesLXDMultiServer).ReleaseQuarantinedInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShoesLXDMulti_ReleaseQuarantinedInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoesLXDMultiServer).ReleaseQuarantinedInstance(ctx, req.(*ReleaseQuarantinedInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ShoesLXDMulti_ServiceDesc is the grpc.ServiceDesc for ShoesLXDMulti service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -140,7 +601,55 @@ var ShoesLXDMulti_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteInstance",
 			Handler:    _ShoesLXDMulti_DeleteInstance_Handler,
 		},
+		{
+			MethodName: "DeleteByRunnerName",
+			Handler:    _ShoesLXDMulti_DeleteByRunnerName_Handler,
+		},
+		{
+			MethodName: "AddInstances",
+			Handler:    _ShoesLXDMulti_AddInstances_Handler,
+		},
+		{
+			MethodName: "DeleteInstances",
+			Handler:    _ShoesLXDMulti_DeleteInstances_Handler,
+		},
+		{
+			MethodName: "CordonHost",
+			Handler:    _ShoesLXDMulti_CordonHost_Handler,
+		},
+		{
+			MethodName: "UncordonHost",
+			Handler:    _ShoesLXDMulti_UncordonHost_Handler,
+		},
+		{
+			MethodName: "ListJournal",
+			Handler:    _ShoesLXDMulti_ListJournal_Handler,
+		},
+		{
+			MethodName: "ListQuarantinedInstances",
+			Handler:    _ShoesLXDMulti_ListQuarantinedInstances_Handler,
+		},
+		{
+			MethodName: "ReleaseQuarantinedInstance",
+			Handler:    _ShoesLXDMulti_ReleaseQuarantinedInstance_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetSetupLog",
+			Handler:       _ShoesLXDMulti_GetSetupLog_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExecInstance",
+			Handler:       _ShoesLXDMulti_ExecInstance_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PullFile",
+			Handler:       _ShoesLXDMulti_PullFile_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "shoeslxdmulti/shoes-lxd-multi.proto",
 }
@@ -2,20 +2,32 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/resourcecache"
 
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/api"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/auth"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/journal"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/prepare"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/tracing"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/webhook"
 )
 
 func main() {
@@ -37,7 +49,109 @@ func run() error {
 		Level:     *logLevel,
 	})))
 
-	go serveMetrics(context.Background(), hostConfigs)
+	shutdownTracing, err := tracing.Setup(ctx, "shoes-lxd-multi-server")
+	if err != nil {
+		return fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Warn("failed to shutdown tracing", "err", err.Error())
+		}
+	}()
+
+	cacheMaxAge, err := config.LoadResourceCacheMaxAge()
+	if err != nil {
+		return fmt.Errorf("failed to load resource cache config: %w", err)
+	}
+	lxdclient.SetStatusCacheMaxAge(cacheMaxAge)
+
+	cbThreshold, cbCooldown, err := config.LoadCircuitBreaker()
+	if err != nil {
+		return fmt.Errorf("failed to load circuit breaker config: %w", err)
+	}
+	lxdclient.SetCircuitBreakerConfig(cbThreshold, cbCooldown)
+
+	shutdownTimeout, err := config.LoadShutdownTimeout()
+	if err != nil {
+		return fmt.Errorf("failed to load shutdown timeout: %w", err)
+	}
+
+	hostConcurrency, err := config.LoadHostConcurrency()
+	if err != nil {
+		return fmt.Errorf("failed to load host concurrency: %w", err)
+	}
+	lxdclient.SetHostConcurrency(hostConcurrency)
+
+	retryPolicy, err := config.LoadRetryPolicy()
+	if err != nil {
+		return fmt.Errorf("failed to load retry policy: %w", err)
+	}
+
+	apiTokens, err := auth.ParseTokens(os.Getenv(config.EnvAPITokens))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", config.EnvAPITokens, err)
+	}
+
+	registrationWait, err := config.LoadRunnerRegistrationWait()
+	if err != nil {
+		return fmt.Errorf("failed to load config of waiting for runner registration: %w", err)
+	}
+
+	quarantine, err := config.LoadQuarantine()
+	if err != nil {
+		return fmt.Errorf("failed to load quarantine config: %w", err)
+	}
+
+	reaper, err := config.LoadReaper()
+	if err != nil {
+		return fmt.Errorf("failed to load reaper config: %w", err)
+	}
+
+	preDeleteHook, err := config.LoadPreDeleteHook()
+	if err != nil {
+		return fmt.Errorf("failed to load pre-delete hook config: %w", err)
+	}
+
+	tombstoneTTL, err := config.LoadDeleteTombstoneTTL()
+	if err != nil {
+		return fmt.Errorf("failed to load tombstone ttl: %w", err)
+	}
+
+	st, err := newStore(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	defer st.Close()
+
+	// background goroutines are stopped after in-flight requests are drained
+	bgCtx, cancelBackground := context.WithCancel(ctx)
+	defer cancelBackground()
+	var wg sync.WaitGroup
+	goBackground := func(f func(ctx context.Context)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f(bgCtx)
+		}()
+	}
+
+	prepareSteps, err := prepare.ParseSteps(os.Getenv(config.EnvPrepareSteps))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", config.EnvPrepareSteps, err)
+	}
+
+	sinks, err := webhook.ParseSinks(os.Getenv(config.EnvWebhooks))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", config.EnvWebhooks, err)
+	}
+	if len(sinks) > 0 {
+		dispatcher := webhook.NewDispatcher(sinks)
+		webhook.SetDefault(dispatcher)
+		goBackground(func(ctx context.Context) { dispatcher.Run(ctx, 4) })
+	}
+
+	metricsListenAddress := config.LoadMetricsListenAddress()
+	goBackground(func(ctx context.Context) { serveMetrics(ctx, hostConfigs, metricsListenAddress) })
 
 	// lxd resource cache
 	var hcs []config.HostConfig
@@ -45,21 +159,123 @@ func run() error {
 		hcs = append(hcs, value)
 		return true
 	})
-	go resourcecache.RunLXDResourceCacheTicker(ctx, hcs, periodSec)
+	goBackground(func(ctx context.Context) { resourcecache.RunLXDResourceCacheTicker(ctx, hcs, periodSec, st) })
 
-	server, err := api.New(hostConfigs, mapping, imageAliasMap, overCommitPercent)
+	j, retention, pendingEntries, err := openJournal()
 	if err != nil {
-		return fmt.Errorf("failed to create server: %w", err)
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer j.Close()
+	if j != nil && retention > 0 {
+		goBackground(func(ctx context.Context) { runJournalPruner(ctx, j, retention) })
 	}
 
-	if err := server.Run(listenPort); err != nil {
-		return fmt.Errorf("faied to run server: %w", err)
+	server, err := api.New(hostConfigs, mapping, imageAliasMap, overCommitPercent, st, j)
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+	server.SetRetryPolicy(retryPolicy)
+	server.SetRunnerRegistrationWait(registrationWait)
+	server.SetAPITokens(apiTokens)
+	server.SetQuarantine(quarantine)
+	server.SetDeleteTombstoneTTL(tombstoneTTL)
+	server.SetPreDeleteHook(preDeleteHook)
+	server.SetPrepareSteps(prepareSteps)
+	server.SetReaper(reaper)
+	goBackground(func(ctx context.Context) { server.ReconcileJournal(ctx, pendingEntries) })
+	if quarantine.Enabled {
+		goBackground(server.RunQuarantineCleaner)
+	}
+	if reaper.Enabled {
+		goBackground(server.RunReaper)
 	}
 
+	sigCtx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	runErr := server.Run(sigCtx, listenPort, shutdownTimeout)
+
+	slog.Info("stopping background goroutines")
+	cancelBackground()
+	wg.Wait()
+
+	if runErr != nil {
+		return fmt.Errorf("faied to run server: %w", runErr)
+	}
+	slog.Info("server is stopped")
 	return nil
 }
 
-func serveMetrics(ctx context.Context, hostConfigs *config.HostConfigMap) {
+func newStore(ctx context.Context) (store.Store, error) {
+	addr, password, db, err := config.LoadRedis()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load redis config: %w", err)
+	}
+	if addr == "" {
+		slog.Info("use in-memory store, state is not shared with other replicas")
+		return store.NewMemory(), nil
+	}
+
+	st, err := store.NewRedis(ctx, addr, password, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect redis: %w", err)
+	}
+	slog.Info("use redis store", "addr", addr)
+	return st, nil
+}
+
+// openJournal open journal and return its retention and entries that are not finished by previous process
+func openJournal() (*journal.Journal, time.Duration, []journal.Entry, error) {
+	path, retention, err := config.LoadJournal()
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to load journal config: %w", err)
+	}
+	if path == "" {
+		slog.Info("journal is disabled")
+		return nil, 0, nil, nil
+	}
+
+	j, err := journal.Open(path)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if retention > 0 {
+		pruneJournal(j, retention)
+	}
+	pending, err := j.ListPending()
+	if err != nil {
+		j.Close()
+		return nil, 0, nil, fmt.Errorf("failed to list pending entries: %w", err)
+	}
+	return j, retention, pending, nil
+}
+
+// journalPruneInterval is interval of deleting journal entries that are older than retention
+const journalPruneInterval = 1 * time.Hour
+
+// runJournalPruner prune journal periodically, the journal grows while the server is running for long time
+func runJournalPruner(ctx context.Context, j *journal.Journal, retention time.Duration) {
+	ticker := time.NewTicker(journalPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruneJournal(j, retention)
+		}
+	}
+}
+
+func pruneJournal(j *journal.Journal, retention time.Duration) {
+	deleted, err := j.Prune(time.Now().Add(-retention))
+	if err != nil {
+		slog.Warn("failed to prune journal", "err", err.Error())
+		return
+	}
+	slog.Info("pruned journal", "deleted", deleted)
+}
+
+func serveMetrics(ctx context.Context, hostConfigs *config.HostConfigMap, listenAddress string) {
 	var hcs []config.HostConfig
 	hostConfigs.Range(func(key string, value config.HostConfig) bool {
 		hcs = append(hcs, value)
@@ -68,11 +284,21 @@ func serveMetrics(ctx context.Context, hostConfigs *config.HostConfigMap) {
 
 	registry := prometheus.NewRegistry()
 	registry.MustRegister(metric.NewCollector(ctx, hcs))
-	registry.MustRegister(metric.FailedLxdAllocate)
+	registry.MustRegister(metric.FailedLxdAllocateTotal)
+	registry.MustRegister(metric.AllocationPhaseDuration)
+	registry.MustRegister(metric.PoolAllocationsTotal)
+	registry.MustRegister(metric.AllocationRetriesTotal)
+	registry.MustRegister(metric.AllocationSetupFailuresTotal)
+	registry.MustRegister(metric.AllocationConflictsTotal)
 	registry.MustRegister(metric.GRPCServerRequestsTotal)
 	registry.MustRegister(metric.GRPCServerRequestDuration)
 	registry.MustRegister(metric.LXDAPIRequestsTotal)
 	registry.MustRegister(metric.LXDAPIRequestDuration)
+	registry.MustRegister(metric.ResourceCacheRefreshDuration)
+	registry.MustRegister(metric.ResourceCacheRefreshErrorsTotal)
+	registry.MustRegister(metric.WebhookDeliveriesTotal)
+	registry.MustRegister(metric.ReaperInstancesTotal)
+	registry.MustRegister(metric.PreDeleteHookTotal)
 	gatherers := prometheus.Gatherers{
 		prometheus.DefaultGatherer,
 		registry,
@@ -85,7 +311,16 @@ func serveMetrics(ctx context.Context, hostConfigs *config.HostConfigMap) {
 		},
 	))
 
-	if err := http.ListenAndServe(":9090", nil); err != nil {
-		log.Fatal("failed to serve metrics (port 9090)", "err", err.Error())
+	srv := &http.Server{Addr: listenAddress}
+	go func() {
+		<-ctx.Done()
+		if err := srv.Shutdown(context.Background()); err != nil {
+			slog.Warn("failed to shutdown metrics server", "err", err.Error())
+		}
+	}()
+
+	slog.Info("start listen metrics", "address", listenAddress)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal("failed to serve metrics", "address", listenAddress, "err", err.Error())
 	}
 }
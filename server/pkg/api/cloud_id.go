@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+)
+
+// cloudIDSeparator is separator of instance name and host in cloud_id.
+// Name of LXD instance can not contain it, so cloud_id that does not contain it is instance name only (older format).
+const cloudIDSeparator = "@"
+
+var (
+	// ErrInstanceIsAmbiguous is error message for instance that has same name in multiple hosts
+	ErrInstanceIsAmbiguous = errors.New("instance name is ambiguous")
+)
+
+// formatCloudID return cloud_id that is qualified by host
+func formatCloudID(lxdHost, instanceName string) string {
+	return instanceName + cloudIDSeparator + lxdHost
+}
+
+// parseCloudID return instance name and host of cloud_id. lxdHost is empty if cloud_id is older format.
+func parseCloudID(cloudID string) (instanceName, lxdHost string) {
+	instanceName, lxdHost, _ = strings.Cut(cloudID, cloudIDSeparator)
+	return instanceName, lxdHost
+}
+
+// findInstanceByCloudID return the host that has the instance of cloud_id, and name of the instance.
+// Host-qualified cloud_id is routed to the host directly, otherwise target hosts are searched.
+func (s *ShoesLXDMultiServer) findInstanceByCloudID(ctx context.Context, targetLXDHosts []*lxdclient.LXDHost, cloudID string, l *slog.Logger) (*lxdclient.LXDHost, string, error) {
+	instanceName, lxdHost := parseCloudID(cloudID)
+	if lxdHost == "" {
+		host, err := s.isExistInstance(ctx, targetLXDHosts, instanceName, l)
+		if err != nil {
+			return nil, "", err
+		}
+		return host, instanceName, nil
+	}
+
+	for _, host := range targetLXDHosts {
+		if host.HostConfig.LxdHost != lxdHost {
+			continue
+		}
+		if err := isExistInstanceWithTimeout(ctx, host, instanceName); err != nil {
+			return nil, "", err
+		}
+		return host, instanceName, nil
+	}
+	return nil, "", fmt.Errorf("%w: %s is not in target hosts", ErrInstanceIsNotFound, lxdHost)
+}
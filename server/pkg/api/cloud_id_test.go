@@ -0,0 +1,36 @@
+package api
+
+import "testing"
+
+func TestParseCloudID(t *testing.T) {
+	tests := []struct {
+		name             string
+		cloudID          string
+		wantInstanceName string
+		wantLXDHost      string
+	}{
+		{
+			name:             "host-qualified",
+			cloudID:          formatCloudID("https://192.0.2.1:8443", "myshoes-runner-0123abcd"),
+			wantInstanceName: "myshoes-runner-0123abcd",
+			wantLXDHost:      "https://192.0.2.1:8443",
+		},
+		{
+			name:             "older format",
+			cloudID:          "myshoes-runner-0123abcd",
+			wantInstanceName: "myshoes-runner-0123abcd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instanceName, lxdHost := parseCloudID(tt.cloudID)
+			if instanceName != tt.wantInstanceName {
+				t.Errorf("instanceName = %q, want %q", instanceName, tt.wantInstanceName)
+			}
+			if lxdHost != tt.wantLXDHost {
+				t.Errorf("lxdHost = %q, want %q", lxdHost, tt.wantLXDHost)
+			}
+		})
+	}
+}
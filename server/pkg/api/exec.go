@@ -0,0 +1,182 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+	lxd "github.com/lxc/lxd/client"
+	"github.com/lxc/lxd/shared/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+)
+
+// streamChunkSize is max size of data in a message of stream
+const streamChunkSize = 32 * 1024
+
+// stopExecTimeout is time to wait for command to exit after sending signal
+const stopExecTimeout = 5 * time.Second
+
+// locateInstance return the host that has the instance and name of the instance, or gRPC error
+func (s *ShoesLXDMultiServer) locateInstance(ctx context.Context, cloudID string, targetHosts []string, l *slog.Logger) (*lxdclient.LXDHost, string, error) {
+	if cloudID == "" {
+		return nil, "", status.Errorf(codes.InvalidArgument, "cloud_id is required")
+	}
+	targetLXDHosts, err := s.validateTargetHosts(ctx, targetHosts, l)
+	if err != nil {
+		return nil, "", status.Errorf(codes.InvalidArgument, "failed to validate target hosts: %+v", err)
+	}
+	host, instanceName, err := s.findInstanceByCloudID(ctx, targetLXDHosts, cloudID, l)
+	if err != nil {
+		return nil, "", findInstanceStatus(err, cloudID)
+	}
+	return host, instanceName, nil
+}
+
+// findInstanceStatus convert error of findInstanceByCloudID to gRPC error
+func findInstanceStatus(err error, cloudID string) error {
+	switch {
+	case errors.Is(err, ErrInstanceIsNotFound):
+		return status.Errorf(codes.NotFound, "failed to found worker that has %s", cloudID)
+	case errors.Is(err, ErrInstanceIsAmbiguous):
+		return status.Errorf(codes.FailedPrecondition, "%+v, use host-qualified cloud_id", err)
+	default:
+		return status.Errorf(codes.Internal, "failed to found worker that has %s", cloudID)
+	}
+}
+
+// execStream execute command in instance and write its stdout and stderr, and return exit code.
+// If ctx is done while executing (e.g. the client cancels), the command is stopped by SIGTERM.
+func execStream(ctx context.Context, host *lxdclient.LXDHost, instanceName string, post api.InstanceExecPost, stdout, stderr io.WriteCloser, l *slog.Logger) (int, error) {
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return -1, fmt.Errorf("acquire lxd client: %w", err)
+	}
+
+	dataDone := make(chan bool)
+	controlCh := make(chan *websocket.Conn, 1)
+	defer func() {
+		select {
+		case conn := <-controlCh:
+			conn.Close()
+		default:
+		}
+	}()
+
+	timer := metric.NewLXDAPITimer(ctx, host.HostConfig.LxdHost, "ExecInstance")
+	op, err := client.ExecInstance(instanceName, post, &lxd.InstanceExecArgs{
+		Stdout:   stdout,
+		Stderr:   stderr,
+		DataDone: dataDone,
+		Control: func(conn *websocket.Conn) {
+			controlCh <- conn
+		},
+	})
+	timer.ObserveDuration(err)
+	// command can take long time (e.g. following log), so do not hold the slot of concurrent API calls while streaming
+	release()
+	if err != nil {
+		return -1, fmt.Errorf("exec instance: %w", err)
+	}
+
+	select {
+	case <-dataDone:
+	case <-ctx.Done():
+		select {
+		case conn := <-controlCh:
+			if err := conn.WriteJSON(api.InstanceExecControl{
+				Command: "signal",
+				Signal:  int(syscall.SIGTERM),
+			}); err != nil {
+				l.Warn("failed to send signal to command", "err", err.Error())
+			}
+			conn.Close()
+		default:
+		}
+		select {
+		case <-dataDone:
+		case <-time.After(stopExecTimeout):
+			l.Warn("command is not exited after sending signal")
+		}
+		return -1, ctx.Err()
+	}
+
+	if err := lxdclient.WaitOperation(ctx, op); err != nil {
+		return -1, fmt.Errorf("waiting operation: %w", err)
+	}
+	code, ok := op.Get().Metadata["return"].(float64)
+	if !ok {
+		return -1, fmt.Errorf("exit code is not found in operation")
+	}
+	return int(code), nil
+}
+
+// streamSender serialize sending messages to gRPC stream from multiple writers.
+// It drops messages after closed, because stream must not be used after the handler returns.
+type streamSender struct {
+	mu     sync.Mutex
+	closed bool
+	err    error
+}
+
+// send call f to send a message if not closed
+func (s *streamSender) send(f func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return io.ErrClosedPipe
+	}
+	if s.err != nil {
+		return s.err
+	}
+	if err := f(); err != nil {
+		s.err = err
+		return err
+	}
+	return nil
+}
+
+// writer return io.WriteCloser that send written data in chunks by send
+func (s *streamSender) writer(send func(data []byte) error) io.WriteCloser {
+	return &streamWriter{sender: s, sendData: send}
+}
+
+// Close stop sending messages to stream
+func (s *streamSender) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+type streamWriter struct {
+	sender   *streamSender
+	sendData func(data []byte) error
+}
+
+// Write send p in chunks
+func (w *streamWriter) Write(p []byte) (int, error) {
+	for i := 0; i < len(p); i += streamChunkSize {
+		end := min(i+streamChunkSize, len(p))
+		// copy data, because p is reused by caller after Write returns
+		data := bytes.Clone(p[i:end])
+		if err := w.sender.send(func() error { return w.sendData(data) }); err != nil {
+			return i, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close does nothing, the stream is closed by streamSender
+func (w *streamWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestStreamSender(t *testing.T) {
+	sender := &streamSender{}
+	var sent [][]byte
+	w := sender.writer(func(data []byte) error {
+		sent = append(sent, data)
+		return nil
+	})
+
+	p := bytes.Repeat([]byte("a"), streamChunkSize+1)
+	n, err := w.Write(p)
+	if err != nil || n != len(p) {
+		t.Fatalf("Write() = (%d, %v), want (%d, nil)", n, err, len(p))
+	}
+	// data must be copied, because the buffer is reused by caller
+	p[0] = 'b'
+	if len(sent) != 2 || len(sent[0]) != streamChunkSize || len(sent[1]) != 1 || sent[0][0] != 'a' {
+		t.Fatalf("sent %d chunks, want 2 chunks split by %d bytes", len(sent), streamChunkSize)
+	}
+
+	sender.Close()
+	if _, err := w.Write([]byte("a")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("Write() after Close returned %v, want %v", err, io.ErrClosedPipe)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("data is sent after Close")
+	}
+}
+
+func TestStreamSender_Error(t *testing.T) {
+	sender := &streamSender{}
+	errSend := errors.New("stream is broken")
+	calls := 0
+	w := sender.writer(func(data []byte) error {
+		calls++
+		return errSend
+	})
+
+	if _, err := w.Write([]byte("a")); !errors.Is(err, errSend) {
+		t.Fatalf("Write() returned %v, want %v", err, errSend)
+	}
+	if _, err := w.Write([]byte("a")); !errors.Is(err, errSend) {
+		t.Fatalf("Write() returned %v, want %v", err, errSend)
+	}
+	if calls != 1 {
+		t.Fatalf("send is called %d times after error, want 1", calls)
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	lxd "github.com/lxc/lxd/client"
+	"github.com/lxc/lxd/shared/api"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+)
+
+// fakeLXD is fake LXD API that has instances, it supports API that is used to stop and delete instances.
+// Instance that is not stopped can not be deleted, same as LXD.
+type fakeLXD struct {
+	mu        sync.Mutex
+	instances map[string]api.StatusCode
+}
+
+func (f *fakeLXD) status(name string) (api.StatusCode, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.instances[name]
+	return s, ok
+}
+
+func (f *fakeLXD) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name, sub, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/1.0/instances/"), "/")
+	status, ok := f.instances[name]
+	if !strings.HasPrefix(r.URL.Path, "/1.0/instances/") || !ok {
+		writeFakeLXDError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && sub == "state":
+		writeFakeLXDResponse(w, "sync", api.InstanceState{Status: status.String(), StatusCode: status})
+	case r.Method == http.MethodPut && sub == "state":
+		var req api.InstanceStatePut
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeFakeLXDError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		switch req.Action {
+		case "stop":
+			f.instances[name] = api.Stopped
+		case "freeze":
+			f.instances[name] = api.Frozen
+		case "start", "unfreeze":
+			f.instances[name] = api.Running
+		}
+		writeFakeLXDResponse(w, "async", api.Operation{ID: "op", Status: api.Success.String(), StatusCode: api.Success})
+	case r.Method == http.MethodDelete && sub == "":
+		if status != api.Stopped {
+			writeFakeLXDError(w, http.StatusBadRequest, "Instance is running")
+			return
+		}
+		delete(f.instances, name)
+		writeFakeLXDResponse(w, "async", api.Operation{ID: "op", Status: api.Success.String(), StatusCode: api.Success})
+	default:
+		writeFakeLXDError(w, http.StatusNotImplemented, "not implemented in fake")
+	}
+}
+
+func writeFakeLXDResponse(w http.ResponseWriter, typ string, metadata any) {
+	m, _ := json.Marshal(metadata)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(api.ResponseRaw{Type: api.ResponseType(typ), Status: api.Success.String(), StatusCode: int(api.Success), Metadata: json.RawMessage(m)})
+}
+
+func writeFakeLXDError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(api.ResponseRaw{Type: api.ErrorResponse, Error: message, Code: code})
+}
+
+// newFakeLXDHost create LXDHost that connect to fakeLXD that has instances
+func newFakeLXDHost(t *testing.T, instances map[string]api.StatusCode) (*lxdclient.LXDHost, *fakeLXD) {
+	t.Helper()
+
+	f := &fakeLXD{instances: instances}
+	srv := httptest.NewTLSServer(f)
+	t.Cleanup(srv.Close)
+
+	c, err := lxd.ConnectLXD(srv.URL, &lxd.ConnectionArgs{
+		InsecureSkipVerify: true,
+		SkipGetServer:      true,
+	})
+	if err != nil {
+		t.Fatalf("failed to connect fake LXD: %+v", err)
+	}
+	return &lxdclient.LXDHost{
+		Client:     c.(*lxd.ProtocolLXD),
+		HostConfig: config.HostConfig{LxdHost: srv.URL},
+	}, f
+}
@@ -6,16 +6,20 @@ import (
 	"fmt"
 	"log/slog"
 	"math/rand"
+	"net/http"
 	"sort"
 	"strconv"
 	"sync"
 	"time"
 
-	lxd "github.com/lxc/lxd/client"
 	"github.com/lxc/lxd/shared/api"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/tracing"
 )
 
 type gotInstances struct {
@@ -80,13 +84,18 @@ func getInstancesWithTimeout(_ctx context.Context, h *lxdclient.LXDHost, d time.
 type instance struct {
 	Host         *lxdclient.LXDHost
 	InstanceName string
+	// Config is config of instance when it is found
+	Config map[string]string
 }
 
 func findInstances(ctx context.Context, targets []*lxdclient.LXDHost, match func(api.Instance) bool, limitOverCommit uint64, l *slog.Logger) []instance {
+	ctx, span := tracing.Tracer().Start(ctx, "findInstances", trace.WithAttributes(attribute.Int("lxd.target_hosts", len(targets))))
+	defer span.End()
+
 	type result struct {
 		host              *lxdclient.LXDHost
 		overCommitPercent uint64
-		instances         []string
+		instances         []api.Instance
 	}
 	rs := make([]result, len(targets))
 
@@ -107,10 +116,10 @@ func findInstances(ctx context.Context, targets []*lxdclient.LXDHost, match func
 				return
 			}
 
-			var instances []string
+			var instances []api.Instance
 			for _, i := range s {
 				if match(i) {
-					instances = append(instances, i.Name)
+					instances = append(instances, i)
 				}
 			}
 
@@ -137,113 +146,388 @@ func findInstances(ctx context.Context, targets []*lxdclient.LXDHost, match func
 		for _, i := range r.instances {
 			instances = append(instances, instance{
 				Host:         r.host,
-				InstanceName: i,
+				InstanceName: i.Name,
+				Config:       i.Config,
 			})
 		}
 	}
+	span.SetAttributes(attribute.Int("lxd.candidates", len(instances)))
 
 	return instances
 }
 
-func findInstanceByJob(ctx context.Context, targets []*lxdclient.LXDHost, runnerName string, l *slog.Logger) (*lxdclient.LXDHost, string, bool) {
+// findInstanceByJob return the instance that is allocated to runnerName by previous request, and its setup state to resume from
+func findInstanceByJob(ctx context.Context, targets []*lxdclient.LXDHost, runnerName string, l *slog.Logger) (*lxdclient.LXDHost, string, string, bool) {
 	s := findInstances(ctx, targets, func(i api.Instance) bool {
-		return i.Config[lxdclient.ConfigKeyRunnerName] == runnerName && i.StatusCode == api.Frozen
+		return isAllocatedInstance(i) && i.Config[lxdclient.ConfigKeyRunnerName] == runnerName
 	}, 0, l)
-	if len(s) < 1 {
-		return nil, "", false
+	return resumeInstanceByJob(ctx, s, runnerName, l)
+}
+
+// isAllocatedInstance return true if the instance is allocated to a runner and can be resumed
+func isAllocatedInstance(i api.Instance) bool {
+	if i.Config[lxdclient.ConfigKeyRunnerName] == "" || isQuarantined(i) {
+		return false
 	}
-	return s[0].Host, s[0].InstanceName, true
+	return i.StatusCode == api.Frozen || i.StatusCode == api.Running
 }
 
-func allocatePooledInstance(ctx context.Context, targets []*lxdclient.LXDHost, resourceType, imageAlias string, limitOverCommit uint64, runnerName string, l *slog.Logger) (*lxdclient.LXDHost, string, error) {
-	s := findInstances(ctx, targets, func(i api.Instance) bool {
-		if i.StatusCode != api.Frozen {
-			return false
-		}
-		if i.Config[lxdclient.ConfigKeyResourceType] != resourceType {
-			return false
-		}
-		if i.Config[lxdclient.ConfigKeyImageAlias] != imageAlias {
-			return false
+// resumeInstanceByJob return the first instance in s that can be resumed, and its setup state to resume from
+func resumeInstanceByJob(ctx context.Context, s []instance, runnerName string, l *slog.Logger) (*lxdclient.LXDHost, string, string, bool) {
+	for _, i := range s {
+		l := l.With("host", i.Host.HostConfig.LxdHost, "instance", i.InstanceName)
+		// status in cache can be stale, so get latest state
+		state, err := getSetupState(ctx, i.Host, i.InstanceName, runnerName)
+		if err != nil {
+			l.Info("instance allocated to runner can not be resumed", "err", err.Error())
+			continue
 		}
-		if _, ok := i.Config[lxdclient.ConfigKeyRunnerName]; ok {
-			return false
+		return i.Host, i.InstanceName, state, true
+	}
+	return nil, "", "", false
+}
+
+// getSetupState return setup state of the instance that is allocated to runnerName
+func getSetupState(ctx context.Context, h *lxdclient.LXDHost, instanceName, runnerName string) (string, error) {
+	c, release, err := h.Acquire(ctx)
+	if err != nil {
+		return "", fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	timer := metric.NewLXDAPITimer(ctx, h.HostConfig.LxdHost, "GetInstance")
+	i, _, err := c.GetInstance(instanceName)
+	timer.ObserveDuration(err)
+	if err != nil {
+		return "", fmt.Errorf("get instance: %w", err)
+	}
+	if i.Config[lxdclient.ConfigKeyRunnerName] != runnerName || isQuarantined(*i) {
+		return "", fmt.Errorf("instance is not allocated to runner")
+	}
+	return resumableSetupState(i.Config[lxdclient.ConfigKeySetupState], i.StatusCode)
+}
+
+// resumableSetupState return setup state to resume from.
+// The instance that is allocated by older version has no state, it can be resumed only if it is not unfrozen yet.
+func resumableSetupState(state string, statusCode api.StatusCode) (string, error) {
+	switch state {
+	case "":
+		if statusCode != api.Frozen {
+			return "", fmt.Errorf("setup state is unknown and instance is %s", statusCode.String())
 		}
-		return true
-	}, limitOverCommit, l)
+		return setupStateAllocated, nil
+	case setupStateAllocated, setupStateUnfrozen, setupStatePrepared, setupStateScriptCopied, setupStateStarted:
+		return state, nil
+	case setupStateRollingBack:
+		return "", fmt.Errorf("instance is being deleted")
+	default:
+		return "", fmt.Errorf("unknown setup state %q", state)
+	}
+}
 
-	for _, i := range s {
+// allocatePooledInstance allocate a pooled instance to runnerName.
+// candidates are tried instead of searching target hosts if not nil (e.g. found by batch request).
+// excluded is set of store.ReservationKey of instances that must not be allocated (e.g. failed to set up in this request).
+func (s *ShoesLXDMultiServer) allocatePooledInstance(ctx context.Context, targets []*lxdclient.LXDHost, resourceType, imageAlias string, limitOverCommit uint64, runnerName string, candidates []instance, excluded map[string]struct{}, l *slog.Logger) (*lxdclient.LXDHost, string, error) {
+	findStartTime := time.Now()
+	instances := candidates
+	if instances == nil {
+		instances = findInstances(ctx, targets, func(i api.Instance) bool {
+			return isPooledInstance(i, resourceType, imageAlias)
+		}, limitOverCommit, l)
+	}
+	metric.ObserveAllocationPhase(ctx, metric.AllocationPhaseFindInstances, findStartTime)
+
+	for _, i := range instances {
 		l := l.With("host", i.Host.HostConfig.LxdHost, "instance", i.InstanceName)
-		if err := allocateInstance(i.Host, i.InstanceName, runnerName, l); err != nil {
+		key := store.ReservationKey(i.Host.HostConfig.LxdHost, i.InstanceName)
+		if _, ok := excluded[key]; ok {
+			continue
+		}
+		token, reserved, err := s.store.Reserve(ctx, key, reservationTTL)
+		if err != nil {
+			// allocateInstance is still protected by etag of LXD
+			l.Warn("failed to reserve instance, allocate without reservation", "err", err.Error())
+			reserved = true
+		}
+		if !reserved {
+			// other request is allocating this instance
+			metric.AllocationConflictsTotal.WithLabelValues(i.Host.HostConfig.LxdHost, metric.AllocationConflictReserved).Inc()
+			continue
+		}
+		allocated, err := allocateInstance(ctx, i.Host, i.InstanceName, runnerName, l)
+		if err != nil {
+			s.releaseReservation(ctx, key, token, l)
+			reason := metric.FailedLxdAllocateError
+			if isAllocationConflict(err) {
+				metric.AllocationConflictsTotal.WithLabelValues(i.Host.HostConfig.LxdHost, metric.AllocationConflictAlreadyAllocated).Inc()
+				reason = metric.FailedLxdAllocateConflict
+			}
 			l.Info("failed to allocate instance (trying another instance)", "err", err)
-			metric.FailedLxdAllocate.WithLabelValues(i.Host.HostConfig.LxdHost, runnerName).Set(1)
+			metric.FailedLxdAllocateTotal.WithLabelValues(i.Host.HostConfig.LxdHost, reason).Inc()
 			continue
 		}
-		metric.FailedLxdAllocate.DeleteLabelValues(i.Host.HostConfig.LxdHost, runnerName)
+		// update cache before release, so other requests does not find this instance as a candidate
+		if err := lxdclient.UpdateInstanceInStatusCache(i.Host.HostConfig.LxdHost, *allocated); err != nil {
+			l.Warn("failed to update status cache", "err", err.Error())
+		}
+		s.releaseReservation(ctx, key, token, l)
 		return i.Host, i.InstanceName, nil
 	}
 
+	if len(instances) == 0 {
+		return nil, "", fmt.Errorf("%w for resource_type=%q image_alias=%q", errPoolExhausted, resourceType, imageAlias)
+	}
 	return nil, "", fmt.Errorf("no available instance for resource_type=%q image_alias=%q", resourceType, imageAlias)
 }
 
-func allocateInstance(host *lxdclient.LXDHost, instanceName, runnerName string, l *slog.Logger) error {
-	host.APICallMutex.Lock()
-	defer host.APICallMutex.Unlock()
-	timer := metric.NewLXDAPITimer(host.HostConfig.LxdHost, "GetInstance")
-	i, etag, err := host.Client.GetInstance(instanceName)
+// isPooledInstance return true if the instance is not allocated and can be allocated for resourceType and imageAlias
+func isPooledInstance(i api.Instance, resourceType, imageAlias string) bool {
+	if i.StatusCode != api.Frozen || isQuarantined(i) {
+		return false
+	}
+	if i.Config[lxdclient.ConfigKeyResourceType] != resourceType {
+		return false
+	}
+	if i.Config[lxdclient.ConfigKeyImageAlias] != imageAlias {
+		return false
+	}
+	if _, ok := i.Config[lxdclient.ConfigKeyRunnerName]; ok {
+		return false
+	}
+	return true
+}
+
+// reservationTTL is lifetime of reservation, it is released automatically if the replica is down while allocating
+const reservationTTL = 1 * time.Minute
+
+func (s *ShoesLXDMultiServer) releaseReservation(ctx context.Context, key, token string, l *slog.Logger) {
+	if token == "" {
+		// not reserved by failure of store
+		return
+	}
+	if err := s.store.Release(context.WithoutCancel(ctx), key, token); err != nil {
+		l.Warn("failed to release reservation", "err", err.Error())
+	}
+}
+
+var (
+	// errAlreadyAllocated is error for instance is allocated by other request
+	errAlreadyAllocated = errors.New("already allocated instance")
+	// errPoolExhausted is error for no pooled instance is found in target hosts
+	errPoolExhausted = errors.New("pool is exhausted")
+)
+
+// isAllocationConflict return true if err is caused by other request that allocate same instance
+func isAllocationConflict(err error) bool {
+	if errors.Is(err, errAlreadyAllocated) {
+		return true
+	}
+	// etag is mismatched
+	_, ok := api.StatusErrorMatch(err, http.StatusPreconditionFailed)
+	return ok
+}
+
+// allocateInstance write runnerName to instance config, and return updated instance
+func allocateInstance(ctx context.Context, host *lxdclient.LXDHost, instanceName, runnerName string, l *slog.Logger) (_ *api.Instance, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "allocateInstance", trace.WithAttributes(
+		attribute.String("lxd.host", host.HostConfig.LxdHost),
+		attribute.String("lxd.instance", instanceName),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	timer := metric.NewLXDAPITimer(ctx, host.HostConfig.LxdHost, "GetInstance")
+	i, etag, err := client.GetInstance(instanceName)
 	timer.ObserveDuration(err)
 	if err != nil {
-		return fmt.Errorf("get instance: %w", err)
+		return nil, fmt.Errorf("get instance: %w", err)
 	}
 
 	if _, ok := i.Config[lxdclient.ConfigKeyRunnerName]; ok {
-		return fmt.Errorf("already allocated instance %q in host %q", instanceName, host.HostConfig.LxdHost)
+		return nil, fmt.Errorf("%w %q in host %q", errAlreadyAllocated, instanceName, host.HostConfig.LxdHost)
 	}
 
 	l.Info("Allocating instance to runner")
 
 	i.InstancePut.Config[lxdclient.ConfigKeyRunnerName] = runnerName
 	i.InstancePut.Config[lxdclient.ConfigKeyAllocatedAt] = time.Now().UTC().Format(time.RFC3339Nano)
+	i.InstancePut.Config[lxdclient.ConfigKeySetupState] = setupStateAllocated
 
-	timer = metric.NewLXDAPITimer(host.HostConfig.LxdHost, "UpdateInstance")
-	op, err := host.Client.UpdateInstance(instanceName, i.InstancePut, etag)
+	timer = metric.NewLXDAPITimer(ctx, host.HostConfig.LxdHost, "UpdateInstance")
+	op, err := client.UpdateInstance(instanceName, i.InstancePut, etag)
 	timer.ObserveDuration(err)
 	if err != nil {
-		return fmt.Errorf("update instance: %w", err)
+		return nil, fmt.Errorf("update instance: %w", err)
 	}
-	if err := op.Wait(); err != nil {
-		return fmt.Errorf("waiting operation: %w", err)
+	if err := lxdclient.WaitOperation(ctx, op); err != nil {
+		return nil, fmt.Errorf("waiting operation: %w", err)
 	}
 
 	// Workaround for https://github.com/canonical/lxd/issues/12189
-	timer = metric.NewLXDAPITimer(host.HostConfig.LxdHost, "GetInstance")
-	i, _, err = host.Client.GetInstance(instanceName)
+	timer = metric.NewLXDAPITimer(ctx, host.HostConfig.LxdHost, "GetInstance")
+	i, _, err = client.GetInstance(instanceName)
 	timer.ObserveDuration(err)
 	if err != nil {
-		return fmt.Errorf("get instance: %w", err)
+		return nil, fmt.Errorf("get instance: %w", err)
 	}
 	if i.Config[lxdclient.ConfigKeyRunnerName] != runnerName {
-		return fmt.Errorf("updated instance config mismatch: got=%q expected=%q", i.Config[lxdclient.ConfigKeyRunnerName], runnerName)
+		return nil, fmt.Errorf("%w: updated instance config mismatch: got=%q expected=%q", errAlreadyAllocated, i.Config[lxdclient.ConfigKeyRunnerName], runnerName)
 	}
 
-	return nil
+	return i, nil
 }
 
-func recoverInvalidInstance(c lxd.InstanceServer, instanceName, host string) error {
-	timer := metric.NewLXDAPITimer(host, "DeleteInstance")
+// destroyInstance stop the instance forcibly if it is not stopped, and delete it
+func destroyInstance(ctx context.Context, h *lxdclient.LXDHost, instanceName string) error {
+	c, release, err := h.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	host := h.HostConfig.LxdHost
+	timer := metric.NewLXDAPITimer(ctx, host, "GetInstanceState")
+	state, etag, err := c.GetInstanceState(instanceName)
+	timer.ObserveDuration(err)
+	if err != nil {
+		return fmt.Errorf("get instance state: %w", err)
+	}
+	if state.StatusCode != api.Stopped {
+		timer = metric.NewLXDAPITimer(ctx, host, "UpdateInstanceState")
+		op, err := c.UpdateInstanceState(instanceName, api.InstanceStatePut{
+			Action:  "stop",
+			Timeout: -1,
+			Force:   true,
+		}, etag)
+		timer.ObserveDuration(err)
+		if err != nil {
+			return fmt.Errorf("stop instance: %w", err)
+		}
+		if err := lxdclient.WaitOperation(ctx, op); err != nil {
+			return fmt.Errorf("waiting operation: %w", err)
+		}
+	}
+
+	timer = metric.NewLXDAPITimer(ctx, host, "DeleteInstance")
 	op, err := c.DeleteInstance(instanceName)
 	timer.ObserveDuration(err)
 	if err != nil {
 		return fmt.Errorf("delete instance: %w", err)
 	}
-	if err := op.Wait(); err != nil {
+	if err := lxdclient.WaitOperation(ctx, op); err != nil {
+		return fmt.Errorf("waiting operation: %w", err)
+	}
+	return nil
+}
+
+// releaseInstance freeze the instance and remove allocation of runnerName, so it can be allocated by other request.
+// It must be called only if nothing is executed in the instance.
+func releaseInstance(ctx context.Context, h *lxdclient.LXDHost, instanceName, runnerName string) error {
+	c, release, err := h.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	host := h.HostConfig.LxdHost
+	timer := metric.NewLXDAPITimer(ctx, host, "GetInstance")
+	i, etag, err := c.GetInstance(instanceName)
+	timer.ObserveDuration(err)
+	if err != nil {
+		return fmt.Errorf("get instance: %w", err)
+	}
+	if i.Config[lxdclient.ConfigKeyRunnerName] != runnerName {
+		return fmt.Errorf("instance is allocated to other runner %q", i.Config[lxdclient.ConfigKeyRunnerName])
+	}
+
+	// freeze before removing allocation, other request must not allocate running instance
+	if i.StatusCode == api.Running {
+		timer = metric.NewLXDAPITimer(ctx, host, "UpdateInstanceState")
+		op, err := c.UpdateInstanceState(instanceName, api.InstanceStatePut{
+			Action:  "freeze",
+			Timeout: -1,
+		}, "")
+		timer.ObserveDuration(err)
+		if err != nil {
+			return fmt.Errorf("freeze instance: %w", err)
+		}
+		if err := lxdclient.WaitOperation(ctx, op); err != nil {
+			return fmt.Errorf("waiting operation: %w", err)
+		}
+		// etag is changed by freezing
+		timer = metric.NewLXDAPITimer(ctx, host, "GetInstance")
+		i, etag, err = c.GetInstance(instanceName)
+		timer.ObserveDuration(err)
+		if err != nil {
+			return fmt.Errorf("get instance: %w", err)
+		}
+	}
+
+	delete(i.InstancePut.Config, lxdclient.ConfigKeyRunnerName)
+	delete(i.InstancePut.Config, lxdclient.ConfigKeyAllocatedAt)
+	delete(i.InstancePut.Config, lxdclient.ConfigKeySetupState)
+	timer = metric.NewLXDAPITimer(ctx, host, "UpdateInstance")
+	op, err := c.UpdateInstance(instanceName, i.InstancePut, etag)
+	timer.ObserveDuration(err)
+	if err != nil {
+		return fmt.Errorf("update instance: %w", err)
+	}
+	if err := lxdclient.WaitOperation(ctx, op); err != nil {
+		return fmt.Errorf("waiting operation: %w", err)
+	}
+	return nil
+}
+
+// recordSetupState record the last completed step of setting up the instance, so the retry of request can resume from it
+func recordSetupState(ctx context.Context, h *lxdclient.LXDHost, instanceName, state string) error {
+	c, release, err := h.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	host := h.HostConfig.LxdHost
+	timer := metric.NewLXDAPITimer(ctx, host, "GetInstance")
+	i, etag, err := c.GetInstance(instanceName)
+	timer.ObserveDuration(err)
+	if err != nil {
+		return fmt.Errorf("get instance: %w", err)
+	}
+
+	i.InstancePut.Config[lxdclient.ConfigKeySetupState] = state
+	timer = metric.NewLXDAPITimer(ctx, host, "UpdateInstance")
+	op, err := c.UpdateInstance(instanceName, i.InstancePut, etag)
+	timer.ObserveDuration(err)
+	if err != nil {
+		return fmt.Errorf("update instance: %w", err)
+	}
+	if err := lxdclient.WaitOperation(ctx, op); err != nil {
 		return fmt.Errorf("waiting operation: %w", err)
 	}
 	return nil
 }
 
-func unfreezeInstance(c lxd.InstanceServer, instanceName, host string) error {
-	timer := metric.NewLXDAPITimer(host, "GetInstanceState")
+func unfreezeInstance(ctx context.Context, h *lxdclient.LXDHost, instanceName string) (err error) {
+	host := h.HostConfig.LxdHost
+	ctx, span := tracing.Tracer().Start(ctx, "unfreezeInstance", trace.WithAttributes(
+		attribute.String("lxd.host", host),
+		attribute.String("lxd.instance", instanceName),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	c, release, err := h.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	timer := metric.NewLXDAPITimer(ctx, host, "GetInstanceState")
 	state, etag, err := c.GetInstanceState(instanceName)
 	timer.ObserveDuration(err)
 	if err != nil {
@@ -253,7 +537,7 @@ func unfreezeInstance(c lxd.InstanceServer, instanceName, host string) error {
 	case api.Running:
 		// do nothing
 	case api.Frozen:
-		timer = metric.NewLXDAPITimer(host, "UpdateInstanceState")
+		timer = metric.NewLXDAPITimer(ctx, host, "UpdateInstanceState")
 		op, err := c.UpdateInstanceState(instanceName, api.InstanceStatePut{
 			Action:  "unfreeze",
 			Timeout: -1,
@@ -262,7 +546,7 @@ func unfreezeInstance(c lxd.InstanceServer, instanceName, host string) error {
 		if err != nil {
 			return fmt.Errorf("update instance state: %w", err)
 		}
-		if err := op.Wait(); err != nil {
+		if err := lxdclient.WaitOperation(ctx, op); err != nil {
 			return fmt.Errorf("waiting operation: %w", err)
 		}
 	default:
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/lxc/lxd/shared/api"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+)
+
+// SetPreDeleteHook set config of command that is executed in instances before deleting
+func (s *ShoesLXDMultiServer) SetPreDeleteHook(h config.PreDeleteHook) {
+	s.preDeleteHook = h
+}
+
+// execPreDeleteHook execute the hook in the instance, it is replaced in tests
+var execPreDeleteHook = execStream
+
+// needForceStop execute pre-delete hook if configured, and return true if the instance should be stopped forcibly
+func (s *ShoesLXDMultiServer) needForceStop(ctx context.Context, host *lxdclient.LXDHost, instanceName, runnerName string, l *slog.Logger) bool {
+	if s.preDeleteHook.Command == "" {
+		return false
+	}
+	// the instance may be hung if the hook is failed
+	return !s.runPreDeleteHook(ctx, host, instanceName, runnerName, l)
+}
+
+// runPreDeleteHook execute pre-delete hook in the instance, and return true if it is exited with zero.
+// The hook is stopped by SIGTERM after timeout.
+func (s *ShoesLXDMultiServer) runPreDeleteHook(ctx context.Context, host *lxdclient.LXDHost, instanceName, runnerName string, l *slog.Logger) bool {
+	hookCtx, cancel := context.WithTimeout(ctx, s.preDeleteHook.Timeout)
+	defer cancel()
+
+	l.Info("will execute pre-delete hook")
+	stdout := &bufferCloser{Buffer: &bytes.Buffer{}}
+	stderr := &bufferCloser{Buffer: &bytes.Buffer{}}
+	code, err := execPreDeleteHook(hookCtx, host, instanceName, api.InstanceExecPost{
+		Command: []string{"/bin/sh", "-c", s.preDeleteHook.Command},
+		Environment: map[string]string{
+			"MYSHOES_RUNNER_NAME": runnerName,
+		},
+	}, stdout, stderr, l)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// deadline of the request is exceeded before timeout of the hook
+		l.Warn("request is canceled while executing pre-delete hook", "err", ctx.Err().Error(), "stdout", stdout.String(), "stderr", stderr.String())
+		metric.PreDeleteHookTotal.WithLabelValues(metric.PreDeleteHookCanceled).Inc()
+		return false
+	case errors.Is(err, context.DeadlineExceeded):
+		l.Warn("pre-delete hook is timed out", "timeout", s.preDeleteHook.Timeout, "stdout", stdout.String(), "stderr", stderr.String())
+		metric.PreDeleteHookTotal.WithLabelValues(metric.PreDeleteHookTimeout).Inc()
+		return false
+	case err != nil:
+		// e.g. the instance is not running
+		l.Warn("failed to execute pre-delete hook", "err", err.Error())
+		metric.PreDeleteHookTotal.WithLabelValues(metric.PreDeleteHookFailed).Inc()
+		return false
+	case code != 0:
+		l.Warn("pre-delete hook is exited with non-zero", "code", code, "stdout", stdout.String(), "stderr", stderr.String())
+		metric.PreDeleteHookTotal.WithLabelValues(metric.PreDeleteHookFailed).Inc()
+		return false
+	}
+	l.Info("pre-delete hook is succeeded")
+	metric.PreDeleteHookTotal.WithLabelValues(metric.PreDeleteHookSucceeded).Inc()
+	return true
+}
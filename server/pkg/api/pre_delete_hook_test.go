@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+)
+
+func TestNeedForceStop(t *testing.T) {
+	host := &lxdclient.LXDHost{HostConfig: config.HostConfig{LxdHost: "test-host"}}
+	hung := func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return -1, fmt.Errorf("wait operation: %w", ctx.Err())
+	}
+
+	tests := []struct {
+		name string
+		hook config.PreDeleteHook
+		// ctxTimeout is deadline of the request, 0 means no deadline
+		ctxTimeout time.Duration
+		exec       func(ctx context.Context) (int, error)
+		want       bool
+		// wantResult is label of metric.PreDeleteHookTotal that is incremented
+		wantResult string
+	}{
+		{
+			name: "hook is not configured",
+			hook: config.PreDeleteHook{},
+			want: false,
+		},
+		{
+			name:       "succeeded",
+			hook:       config.PreDeleteHook{Command: "true", Timeout: time.Second},
+			exec:       func(context.Context) (int, error) { return 0, nil },
+			want:       false,
+			wantResult: metric.PreDeleteHookSucceeded,
+		},
+		{
+			name:       "exited with non-zero",
+			hook:       config.PreDeleteHook{Command: "false", Timeout: time.Second},
+			exec:       func(context.Context) (int, error) { return 1, nil },
+			want:       true,
+			wantResult: metric.PreDeleteHookFailed,
+		},
+		{
+			name:       "failed to execute",
+			hook:       config.PreDeleteHook{Command: "true", Timeout: time.Second},
+			exec:       func(context.Context) (int, error) { return -1, errors.New("instance is not running") },
+			want:       true,
+			wantResult: metric.PreDeleteHookFailed,
+		},
+		{
+			name:       "timed out",
+			hook:       config.PreDeleteHook{Command: "sleep 60", Timeout: 10 * time.Millisecond},
+			exec:       hung,
+			want:       true,
+			wantResult: metric.PreDeleteHookTimeout,
+		},
+		{
+			name:       "deadline of request is exceeded",
+			hook:       config.PreDeleteHook{Command: "sleep 60", Timeout: time.Minute},
+			ctxTimeout: 10 * time.Millisecond,
+			exec:       hung,
+			want:       true,
+			wantResult: metric.PreDeleteHookCanceled,
+		},
+	}
+
+	orig := execPreDeleteHook
+	t.Cleanup(func() { execPreDeleteHook = orig })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			execPreDeleteHook = func(ctx context.Context, _ *lxdclient.LXDHost, _ string, post api.InstanceExecPost, _, _ io.WriteCloser, _ *slog.Logger) (int, error) {
+				called = true
+				if post.Environment["MYSHOES_RUNNER_NAME"] != "runner-1" {
+					t.Errorf("MYSHOES_RUNNER_NAME = %q, want %q", post.Environment["MYSHOES_RUNNER_NAME"], "runner-1")
+				}
+				return tt.exec(ctx)
+			}
+
+			ctx := context.Background()
+			if tt.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctxTimeout)
+				defer cancel()
+			}
+			var before float64
+			if tt.wantResult != "" {
+				before = testutil.ToFloat64(metric.PreDeleteHookTotal.WithLabelValues(tt.wantResult))
+			}
+			s := &ShoesLXDMultiServer{}
+			s.SetPreDeleteHook(tt.hook)
+			if got := s.needForceStop(ctx, host, "instance-1", "runner-1", slog.Default()); got != tt.want {
+				t.Errorf("needForceStop() = %v, want %v", got, tt.want)
+			}
+			if tt.wantResult != "" {
+				if got := testutil.ToFloat64(metric.PreDeleteHookTotal.WithLabelValues(tt.wantResult)) - before; got != 1 {
+					t.Errorf("result %q is incremented by %v, want 1", tt.wantResult, got)
+				}
+			}
+			if called != (tt.exec != nil) {
+				t.Errorf("hook is called = %v, want %v", called, tt.exec != nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+
+	lxd "github.com/lxc/lxd/client"
+	"github.com/lxc/lxd/shared/api"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/prepare"
+)
+
+// SetPrepareSteps set steps that are executed in allocated instances before setup script
+func (s *ShoesLXDMultiServer) SetPrepareSteps(steps []prepare.Step) {
+	s.prepareSteps = steps
+}
+
+// prepareInstance execute preparation steps in the unfrozen instance in order
+func (s *ShoesLXDMultiServer) prepareInstance(ctx context.Context, host *lxdclient.LXDHost, instanceName string, req *pb.AddInstanceRequest, l *slog.Logger) error {
+	d := prepare.Data{
+		RunnerName:   req.RunnerName,
+		Host:         host.HostConfig.LxdHost,
+		InstanceName: instanceName,
+		Labels:       req.Labels,
+		ImageAlias:   s.parseImageAliasMap(req.OsVersion),
+		ResourceType: datastore.UnmarshalResourceTypePb(req.ResourceType).String(),
+	}
+	for idx, step := range s.prepareSteps {
+		name := step.DisplayName(idx)
+		err := runPrepareStep(ctx, host, instanceName, step, d, l.With("step", name))
+		if err == nil {
+			continue
+		}
+		if step.IgnoreFailure {
+			l.Warn("preparation step is failed, ignore it", "step", name, "err", err.Error())
+			continue
+		}
+		return fmt.Errorf("step %s: %w", name, err)
+	}
+	return nil
+}
+
+func runPrepareStep(ctx context.Context, host *lxdclient.LXDHost, instanceName string, step prepare.Step, d prepare.Data, l *slog.Logger) error {
+	a, err := step.Render(d)
+	if err != nil {
+		return err
+	}
+
+	switch a.Type {
+	case prepare.StepTypeExec:
+		ctx, cancel := context.WithTimeout(ctx, a.Timeout)
+		defer cancel()
+		stdout := &bufferCloser{Buffer: &bytes.Buffer{}}
+		stderr := &bufferCloser{Buffer: &bytes.Buffer{}}
+		code, err := execStream(ctx, host, instanceName, api.InstanceExecPost{Command: a.Command}, stdout, stderr, l)
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			return fmt.Errorf("exited with %d: %s", code, stderr.String())
+		}
+		return nil
+	default:
+		client, release, err := host.Acquire(ctx)
+		if err != nil {
+			return fmt.Errorf("acquire lxd client: %w", err)
+		}
+		defer release()
+
+		timer := metric.NewLXDAPITimer(ctx, host.HostConfig.LxdHost, "CreateInstanceFile")
+		err = client.CreateInstanceFile(instanceName, a.Path, lxd.InstanceFileArgs{
+			Content:   bytes.NewReader(a.Content),
+			Mode:      a.Mode,
+			Type:      "file",
+			WriteMode: "overwrite",
+		})
+		timer.ObserveDuration(err)
+		if err != nil {
+			return fmt.Errorf("push file: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,267 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/webhook"
+)
+
+const (
+	// QuarantineReasonUnfreezeFailed is reason of quarantine that unfreezing instance is failed
+	QuarantineReasonUnfreezeFailed = "unfreeze_failed"
+	// QuarantineReasonPrepareFailed is reason of quarantine that preparation step is failed
+	QuarantineReasonPrepareFailed = "prepare_failed"
+	// QuarantineReasonCopyFailed is reason of quarantine that copying setup script is failed after preparation steps
+	QuarantineReasonCopyFailed = "copy_failed"
+	// QuarantineReasonSetupScriptFailed is reason of quarantine that setup script is exited with non-zero
+	QuarantineReasonSetupScriptFailed = "setup_script_failed"
+	// QuarantineReasonRegistrationFailed is reason of quarantine that the runner is not registered
+	QuarantineReasonRegistrationFailed = "registration_failed"
+)
+
+// quarantineCleanInterval is interval of deleting quarantined instances that are expired
+const quarantineCleanInterval = 10 * time.Minute
+
+// SetQuarantine set config of quarantine
+func (s *ShoesLXDMultiServer) SetQuarantine(q config.Quarantine) {
+	s.quarantine = q
+}
+
+// isQuarantined return true if the instance is quarantined
+func isQuarantined(i api.Instance) bool {
+	return i.Config[lxdclient.ConfigKeyQuarantined] != ""
+}
+
+// quarantineFailedInstance quarantine the instance if quarantine is enabled, and return true if quarantined.
+// The caller should handle the instance as before (e.g. delete) if false is returned.
+func (s *ShoesLXDMultiServer) quarantineFailedInstance(ctx context.Context, host *lxdclient.LXDHost, instanceName, reason string, l *slog.Logger) bool {
+	if !s.quarantine.Enabled {
+		return false
+	}
+	if err := quarantineInstance(context.WithoutCancel(ctx), host, instanceName, reason); err != nil {
+		l.Error("failed to quarantine instance", "reason", reason, "err", err.Error())
+		return false
+	}
+	l.Warn("quarantined instance", "reason", reason)
+	webhook.Emit(webhook.Event{
+		Type:         webhook.EventInstanceQuarantined,
+		Host:         host.HostConfig.LxdHost,
+		InstanceName: instanceName,
+		Message:      reason,
+	})
+	return true
+}
+
+// quarantineInstance record reason to instance config and freeze it, so it is kept for investigation
+func quarantineInstance(ctx context.Context, host *lxdclient.LXDHost, instanceName, reason string) error {
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	hostAddr := host.HostConfig.LxdHost
+	timer := metric.NewLXDAPITimer(ctx, hostAddr, "GetInstance")
+	i, etag, err := client.GetInstance(instanceName)
+	timer.ObserveDuration(err)
+	if err != nil {
+		return fmt.Errorf("get instance: %w", err)
+	}
+
+	i.InstancePut.Config[lxdclient.ConfigKeyQuarantined] = reason
+	i.InstancePut.Config[lxdclient.ConfigKeyQuarantinedAt] = time.Now().UTC().Format(time.RFC3339Nano)
+	timer = metric.NewLXDAPITimer(ctx, hostAddr, "UpdateInstance")
+	op, err := client.UpdateInstance(instanceName, i.InstancePut, etag)
+	timer.ObserveDuration(err)
+	if err != nil {
+		return fmt.Errorf("update instance: %w", err)
+	}
+	if err := lxdclient.WaitOperation(ctx, op); err != nil {
+		return fmt.Errorf("waiting operation: %w", err)
+	}
+
+	if i.StatusCode != api.Running {
+		return nil
+	}
+	timer = metric.NewLXDAPITimer(ctx, hostAddr, "UpdateInstanceState")
+	op, err = client.UpdateInstanceState(instanceName, api.InstanceStatePut{
+		Action:  "freeze",
+		Timeout: -1,
+	}, "")
+	timer.ObserveDuration(err)
+	if err != nil {
+		return fmt.Errorf("freeze instance: %w", err)
+	}
+	if err := lxdclient.WaitOperation(ctx, op); err != nil {
+		return fmt.Errorf("waiting operation: %w", err)
+	}
+	return nil
+}
+
+// listQuarantinedInstances return quarantined instances in hosts
+func listQuarantinedInstances(ctx context.Context, host *lxdclient.LXDHost) ([]api.Instance, error) {
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	instances, err := lxdclient.GetAnyInstances(ctx, client, host.HostConfig.LxdHost)
+	if err != nil {
+		return nil, fmt.Errorf("get instances: %w", err)
+	}
+	var quarantined []api.Instance
+	for _, i := range instances {
+		if isQuarantined(i) {
+			quarantined = append(quarantined, i)
+		}
+	}
+	return quarantined, nil
+}
+
+// quarantinedAt return time that the instance is quarantined
+func quarantinedAt(i api.Instance) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, i.Config[lxdclient.ConfigKeyQuarantinedAt])
+}
+
+// quarantineTargetHosts return targetHosts, or all configured hosts if empty
+func (s *ShoesLXDMultiServer) quarantineTargetHosts(targetHosts []string) []string {
+	if len(targetHosts) > 0 {
+		return targetHosts
+	}
+	var hosts []string
+	s.hostConfigs.Range(func(key string, value config.HostConfig) bool {
+		hosts = append(hosts, key)
+		return true
+	})
+	return hosts
+}
+
+// ListQuarantinedInstances return instances that are quarantined
+func (s *ShoesLXDMultiServer) ListQuarantinedInstances(ctx context.Context, req *pb.ListQuarantinedInstancesRequest) (*pb.ListQuarantinedInstancesResponse, error) {
+	l := slog.With("method", "ListQuarantinedInstances")
+	targetLXDHosts, err := s.validateTargetHosts(ctx, s.quarantineTargetHosts(req.TargetHosts), l)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to validate target hosts: %+v", err)
+	}
+
+	resp := &pb.ListQuarantinedInstancesResponse{}
+	for _, host := range targetLXDHosts {
+		instances, err := listQuarantinedInstances(ctx, host)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list quarantined instances in %s: %+v", host.HostConfig.LxdHost, err)
+		}
+		for _, i := range instances {
+			qi := &pb.QuarantinedInstance{
+				Host:         host.HostConfig.LxdHost,
+				InstanceName: i.Name,
+				RunnerName:   i.Config[lxdclient.ConfigKeyRunnerName],
+				Reason:       i.Config[lxdclient.ConfigKeyQuarantined],
+				Status:       i.Status,
+			}
+			if t, err := quarantinedAt(i); err == nil {
+				qi.QuarantinedAt = timestamppb.New(t)
+			}
+			resp.Instances = append(resp.Instances, qi)
+		}
+	}
+	return resp, nil
+}
+
+// ReleaseQuarantinedInstance delete quarantined instance
+func (s *ShoesLXDMultiServer) ReleaseQuarantinedInstance(ctx context.Context, req *pb.ReleaseQuarantinedInstanceRequest) (*pb.ReleaseQuarantinedInstanceResponse, error) {
+	l := slog.With("method", "ReleaseQuarantinedInstance", "instanceName", req.CloudId)
+	host, instanceName, err := s.locateInstance(ctx, req.CloudId, req.TargetHosts, l)
+	if err != nil {
+		return nil, err
+	}
+
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to acquire lxd client: %+v", err)
+	}
+	timer := metric.NewLXDAPITimer(ctx, host.HostConfig.LxdHost, "GetInstance")
+	i, _, err := client.GetInstance(instanceName)
+	timer.ObserveDuration(err)
+	release()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get instance: %+v", err)
+	}
+	if !isQuarantined(*i) {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s is not quarantined", req.CloudId)
+	}
+
+	if err := destroyInstance(ctx, host, instanceName); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete quarantined instance: %+v", err)
+	}
+	l.Info("released quarantined instance", "host", host.HostConfig.LxdHost)
+	return &pb.ReleaseQuarantinedInstanceResponse{}, nil
+}
+
+// RunQuarantineCleaner delete quarantined instances that are older than retention periodically
+func (s *ShoesLXDMultiServer) RunQuarantineCleaner(ctx context.Context) {
+	if !s.quarantine.Enabled || s.quarantine.Retention == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(quarantineCleanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.cleanQuarantinedInstances(ctx)
+		}
+	}
+}
+
+func (s *ShoesLXDMultiServer) cleanQuarantinedInstances(ctx context.Context) {
+	l := slog.With("method", "cleanQuarantinedInstances")
+	targetLXDHosts, err := s.validateTargetHosts(ctx, s.quarantineTargetHosts(nil), l)
+	if err != nil {
+		l.Warn("failed to validate target hosts", "err", err.Error())
+		return
+	}
+
+	for _, host := range targetLXDHosts {
+		l := l.With("host", host.HostConfig.LxdHost)
+		instances, err := listQuarantinedInstances(ctx, host)
+		if err != nil {
+			l.Warn("failed to list quarantined instances", "err", err.Error())
+			continue
+		}
+		for _, i := range instances {
+			if !isQuarantineExpired(i, s.quarantine.Retention, time.Now()) {
+				continue
+			}
+			if err := destroyInstance(ctx, host, i.Name); err != nil {
+				l.Warn("failed to delete expired quarantined instance", "instance", i.Name, "err", err.Error())
+				continue
+			}
+			l.Info("deleted expired quarantined instance", "instance", i.Name, "reason", i.Config[lxdclient.ConfigKeyQuarantined])
+		}
+	}
+}
+
+// isQuarantineExpired return true if the instance is quarantined before retention.
+// The instance that quarantined time is unknown is expired, because it can not be released automatically otherwise.
+func isQuarantineExpired(i api.Instance, retention time.Duration, now time.Time) bool {
+	t, err := quarantinedAt(i)
+	if err != nil {
+		return true
+	}
+	return t.Add(retention).Before(now)
+}
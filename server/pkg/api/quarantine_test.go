@@ -0,0 +1,57 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+)
+
+func TestIsQuarantineExpired(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	retention := 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		config map[string]string
+		want   bool
+	}{
+		{
+			name: "in retention",
+			config: map[string]string{
+				lxdclient.ConfigKeyQuarantined:   QuarantineReasonSetupScriptFailed,
+				lxdclient.ConfigKeyQuarantinedAt: now.Add(-time.Hour).Format(time.RFC3339Nano),
+			},
+			want: false,
+		},
+		{
+			name: "expired",
+			config: map[string]string{
+				lxdclient.ConfigKeyQuarantined:   QuarantineReasonSetupScriptFailed,
+				lxdclient.ConfigKeyQuarantinedAt: now.Add(-25 * time.Hour).Format(time.RFC3339Nano),
+			},
+			want: true,
+		},
+		{
+			name: "unknown quarantined time",
+			config: map[string]string{
+				lxdclient.ConfigKeyQuarantined: QuarantineReasonUnfreezeFailed,
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := api.Instance{InstancePut: api.InstancePut{Config: tt.config}}
+			if !isQuarantined(i) {
+				t.Fatalf("isQuarantined() = false, want true")
+			}
+			if got := isQuarantineExpired(i, retention, now); got != tt.want {
+				t.Errorf("isQuarantineExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
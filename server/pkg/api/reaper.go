@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/webhook"
+)
+
+const (
+	// ReapReasonMaxLifetimeExceeded is reason of reaping that the instance is allocated before max lifetime
+	ReapReasonMaxLifetimeExceeded = "max_lifetime_exceeded"
+	// ReapReasonStopped is reason of reaping that the allocated instance is stopped
+	ReapReasonStopped = "stopped"
+	// ReapReasonSetupNotStarted is reason of reaping that setup script is not started in the allocated instance
+	ReapReasonSetupNotStarted = "setup_not_started"
+)
+
+// SetReaper set config of reaper
+func (s *ShoesLXDMultiServer) SetReaper(r config.Reaper) {
+	s.reaper = r
+}
+
+// RunReaper reap leaked instances that are allocated to runners periodically
+func (s *ShoesLXDMultiServer) RunReaper(ctx context.Context) {
+	if !s.reaper.Enabled {
+		return
+	}
+
+	ticker := time.NewTicker(s.reaper.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reapInstances(ctx)
+		}
+	}
+}
+
+func (s *ShoesLXDMultiServer) reapInstances(ctx context.Context) {
+	l := slog.With("method", "reapInstances", "dryRun", s.reaper.DryRun)
+	targetLXDHosts, err := s.validateTargetHosts(ctx, s.quarantineTargetHosts(nil), l)
+	if err != nil {
+		l.Warn("failed to validate target hosts", "err", err.Error())
+		return
+	}
+
+	for _, host := range targetLXDHosts {
+		l := l.With("host", host.HostConfig.LxdHost)
+		instances, err := listAllocatedInstances(ctx, host)
+		if err != nil {
+			l.Warn("failed to list allocated instances", "err", err.Error())
+			continue
+		}
+		for _, i := range instances {
+			reason := reapReason(i, s.reaper, time.Now())
+			if reason == "" {
+				continue
+			}
+			s.reapInstance(ctx, host, i, reason, l.With("instance", i.Name, "runnerName", i.Config[lxdclient.ConfigKeyRunnerName], "reason", reason))
+		}
+	}
+}
+
+// reapInstance delete or quarantine the leaked instance
+func (s *ShoesLXDMultiServer) reapInstance(ctx context.Context, host *lxdclient.LXDHost, i api.Instance, reason string, l *slog.Logger) {
+	if s.reaper.DryRun {
+		l.Info("found leaked instance (dry-run)")
+		metric.ReaperInstancesTotal.WithLabelValues(reason, metric.ReaperActionDryRun).Inc()
+		return
+	}
+
+	// other replica may reap or resume the instance at the same time
+	key := store.ReservationKey(host.HostConfig.LxdHost, i.Name)
+	token, reserved, err := s.store.Reserve(ctx, key, reservationTTL)
+	if err != nil {
+		l.Warn("failed to reserve instance", "err", err.Error())
+		return
+	}
+	if !reserved {
+		return
+	}
+	defer s.releaseReservation(ctx, key, token, l)
+
+	// the instance may be changed after listing (e.g. resumed or deleted)
+	latest, err := getInstance(ctx, host, i.Name)
+	if err != nil {
+		l.Warn("failed to get instance", "err", err.Error())
+		return
+	}
+	if latest.Config[lxdclient.ConfigKeyRunnerName] != i.Config[lxdclient.ConfigKeyRunnerName] || isQuarantined(*latest) || reapReason(*latest, s.reaper, time.Now()) == "" {
+		l.Info("instance is changed after listing, skip reaping")
+		return
+	}
+
+	if s.quarantineFailedInstance(ctx, host, i.Name, reason, l) {
+		metric.ReaperInstancesTotal.WithLabelValues(reason, metric.ReaperActionQuarantined).Inc()
+		return
+	}
+
+	runnerName := i.Config[lxdclient.ConfigKeyRunnerName]
+	// the runner may be deleted by myshoes later, it should succeed
+	cloudID := formatCloudID(host.HostConfig.LxdHost, i.Name)
+	s.saveTombstone(ctx, tombstoneKeyCloudID(cloudID), runnerName, l)
+	s.saveTombstone(ctx, tombstoneKeyRunnerName(runnerName), cloudID, l)
+	if err := destroyInstance(ctx, host, i.Name); err != nil {
+		l.Warn("failed to delete leaked instance", "err", err.Error())
+		metric.ReaperInstancesTotal.WithLabelValues(reason, metric.ReaperActionFailed).Inc()
+		return
+	}
+	l.Info("deleted leaked instance")
+	metric.ReaperInstancesTotal.WithLabelValues(reason, metric.ReaperActionDeleted).Inc()
+	webhook.Emit(webhook.Event{
+		Type:         webhook.EventInstanceDeleted,
+		RunnerName:   runnerName,
+		Host:         host.HostConfig.LxdHost,
+		InstanceName: i.Name,
+		Message:      reason,
+	})
+}
+
+// getInstance return latest instance
+func getInstance(ctx context.Context, host *lxdclient.LXDHost, instanceName string) (*api.Instance, error) {
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	timer := metric.NewLXDAPITimer(ctx, host.HostConfig.LxdHost, "GetInstance")
+	i, _, err := client.GetInstance(instanceName)
+	timer.ObserveDuration(err)
+	if err != nil {
+		return nil, fmt.Errorf("get instance: %w", err)
+	}
+	return i, nil
+}
+
+// listAllocatedInstances return instances in host that are allocated to runners, except quarantined instances
+func listAllocatedInstances(ctx context.Context, host *lxdclient.LXDHost) ([]api.Instance, error) {
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	instances, err := lxdclient.GetAnyInstances(ctx, client, host.HostConfig.LxdHost)
+	if err != nil {
+		return nil, fmt.Errorf("get instances: %w", err)
+	}
+	var allocated []api.Instance
+	for _, i := range instances {
+		if i.Config[lxdclient.ConfigKeyRunnerName] != "" && !isQuarantined(i) {
+			allocated = append(allocated, i)
+		}
+	}
+	return allocated, nil
+}
+
+// reapReason return reason of reaping the allocated instance, or empty if the instance is not leaked
+func reapReason(i api.Instance, r config.Reaper, now time.Time) string {
+	age := now.Sub(allocatedAt(i))
+	switch {
+	case r.MaxLifetime > 0 && age > r.MaxLifetime:
+		return ReapReasonMaxLifetimeExceeded
+	case age <= r.SetupTimeout:
+		// the instance may be set up or deleted by in-flight request
+		return ""
+	case i.StatusCode == api.Stopped:
+		return ReapReasonStopped
+	}
+
+	switch i.Config[lxdclient.ConfigKeySetupState] {
+	case setupStateAllocated, setupStateUnfrozen, setupStatePrepared, setupStateScriptCopied, setupStateRollingBack:
+		return ReapReasonSetupNotStarted
+	case "":
+		// allocated by older version, setup script is not started if it is not unfrozen yet
+		if i.StatusCode == api.Frozen {
+			return ReapReasonSetupNotStarted
+		}
+	}
+	return ""
+}
+
+// allocatedAt return time that the instance is allocated, or created time if unknown
+func allocatedAt(i api.Instance) time.Time {
+	if t, err := time.Parse(time.RFC3339Nano, i.Config[lxdclient.ConfigKeyAllocatedAt]); err == nil {
+		return t
+	}
+	return i.CreatedAt
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+)
+
+func TestReapReason(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	r := config.Reaper{MaxLifetime: 24 * time.Hour, SetupTimeout: 30 * time.Minute}
+
+	allocated := func(age time.Duration, state string) map[string]string {
+		return map[string]string{
+			lxdclient.ConfigKeyRunnerName:  "myshoes-00000000-0000-0000-0000-000000000000",
+			lxdclient.ConfigKeyAllocatedAt: now.Add(-age).Format(time.RFC3339Nano),
+			lxdclient.ConfigKeySetupState:  state,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		config     map[string]string
+		statusCode api.StatusCode
+		createdAt  time.Time
+		want       string
+	}{
+		{
+			name:       "running job",
+			config:     allocated(2*time.Hour, setupStateStarted),
+			statusCode: api.Running,
+			want:       "",
+		},
+		{
+			name:       "max lifetime exceeded",
+			config:     allocated(25*time.Hour, setupStateStarted),
+			statusCode: api.Running,
+			want:       ReapReasonMaxLifetimeExceeded,
+		},
+		{
+			name:       "stopped",
+			config:     allocated(time.Hour, setupStateStarted),
+			statusCode: api.Stopped,
+			want:       ReapReasonStopped,
+		},
+		{
+			name:       "stopped in setup timeout",
+			config:     allocated(time.Minute, setupStateStarted),
+			statusCode: api.Stopped,
+			want:       "",
+		},
+		{
+			name:       "setup not started",
+			config:     allocated(time.Hour, setupStateScriptCopied),
+			statusCode: api.Running,
+			want:       ReapReasonSetupNotStarted,
+		},
+		{
+			name:       "failed to delete canceled instance",
+			config:     allocated(time.Hour, setupStateRollingBack),
+			statusCode: api.Running,
+			want:       ReapReasonSetupNotStarted,
+		},
+		{
+			name:       "setting up",
+			config:     allocated(time.Minute, setupStateAllocated),
+			statusCode: api.Frozen,
+			want:       "",
+		},
+		{
+			name: "allocated by older version and not unfrozen",
+			config: map[string]string{
+				lxdclient.ConfigKeyRunnerName: "myshoes-00000000-0000-0000-0000-000000000000",
+			},
+			statusCode: api.Frozen,
+			createdAt:  now.Add(-time.Hour),
+			want:       ReapReasonSetupNotStarted,
+		},
+		{
+			name: "allocated by older version and running",
+			config: map[string]string{
+				lxdclient.ConfigKeyRunnerName: "myshoes-00000000-0000-0000-0000-000000000000",
+			},
+			statusCode: api.Running,
+			createdAt:  now.Add(-time.Hour),
+			want:       "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := api.Instance{
+				InstancePut: api.InstancePut{Config: tt.config},
+				CreatedAt:   tt.createdAt,
+				StatusCode:  tt.statusCode,
+			}
+			if got := reapReason(i, r, now); got != tt.want {
+				t.Errorf("reapReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	noLimit := r
+	noLimit.MaxLifetime = 0
+	i := api.Instance{InstancePut: api.InstancePut{Config: allocated(48*time.Hour, setupStateStarted)}, StatusCode: api.Running}
+	if got := reapReason(i, noLimit, now); got != "" {
+		t.Errorf("reapReason() without max lifetime = %q, want empty", got)
+	}
+}
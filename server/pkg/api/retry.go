@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+)
+
+// SetRetryPolicy set policy of retrying allocation
+func (s *ShoesLXDMultiServer) SetRetryPolicy(p config.RetryPolicy) {
+	s.retryPolicy = p
+}
+
+// retry call f until it succeeds or retry policy is exhausted, and return the last error of f.
+// It gives up without waiting if ctx is done or the next attempt can not start before deadline of ctx,
+// so the caller should check ctx.Err() to know whether the request is canceled.
+// onRetry is called before waiting for each retry.
+func retry(ctx context.Context, p config.RetryPolicy, f func() error, onRetry func(attempt int, err error)) error {
+	startTime := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		if attempt >= p.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		wait := backoff(p, attempt)
+		if p.Budget > 0 && time.Since(startTime)+wait > p.Budget {
+			return err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return err
+		}
+
+		onRetry(attempt, err)
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
+
+// backoff return wait time before retry after attempt-th attempt.
+// It is exponential backoff with equal jitter, so requests that failed at the same time do not conflict again.
+func backoff(p config.RetryPolicy, attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+)
+
+func TestBackoff(t *testing.T) {
+	p := config.RetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     1 * time.Second,
+	}
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 1, max: 100 * time.Millisecond},
+		{attempt: 2, max: 200 * time.Millisecond},
+		{attempt: 4, max: 800 * time.Millisecond},
+		{attempt: 5, max: 1 * time.Second},
+		{attempt: 100, max: 1 * time.Second},
+	}
+	for _, tt := range tests {
+		for range 100 {
+			got := backoff(p, tt.attempt)
+			if got < tt.max/2 || got > tt.max {
+				t.Fatalf("backoff(%d) = %s, want in [%s, %s]", tt.attempt, got, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	p := config.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}
+	errFailed := errors.New("failed")
+
+	t.Run("succeed after retry", func(t *testing.T) {
+		attempts, retries := 0, 0
+		err := retry(context.Background(), p, func() error {
+			attempts++
+			if attempts < 2 {
+				return errFailed
+			}
+			return nil
+		}, func(int, error) { retries++ })
+		if err != nil {
+			t.Fatalf("retry() returned error: %+v", err)
+		}
+		if attempts != 2 || retries != 1 {
+			t.Fatalf("attempts = %d, retries = %d, want 2, 1", attempts, retries)
+		}
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		attempts := 0
+		err := retry(context.Background(), p, func() error {
+			attempts++
+			return errFailed
+		}, func(int, error) {})
+		if !errors.Is(err, errFailed) {
+			t.Fatalf("retry() returned %v, want %v", err, errFailed)
+		}
+		if attempts != p.MaxAttempts {
+			t.Fatalf("attempts = %d, want %d", attempts, p.MaxAttempts)
+		}
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		p := p
+		p.InitialBackoff, p.MaxBackoff, p.Budget = time.Hour, time.Hour, time.Minute
+		attempts := 0
+		err := retry(context.Background(), p, func() error {
+			attempts++
+			return errFailed
+		}, func(int, error) {})
+		if !errors.Is(err, errFailed) || attempts != 1 {
+			t.Fatalf("retry() returned %v after %d attempts, want %v after 1 attempt", err, attempts, errFailed)
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		p := p
+		p.InitialBackoff, p.MaxBackoff = time.Hour, time.Hour
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		attempts := 0
+		retry(ctx, p, func() error {
+			attempts++
+			return errFailed
+		}, func(int, error) {})
+		if attempts != 1 {
+			t.Fatalf("attempts = %d, want 1", attempts)
+		}
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		p := p
+		p.InitialBackoff, p.MaxBackoff = time.Hour, time.Hour
+		ctx, cancel := context.WithCancel(context.Background())
+		attempts := 0
+		err := retry(ctx, p, func() error {
+			attempts++
+			return errFailed
+		}, func(int, error) { cancel() })
+		if !errors.Is(err, errFailed) || attempts != 1 || ctx.Err() == nil {
+			t.Fatalf("retry() returned %v after %d attempts, want %v after 1 attempt", err, attempts, errFailed)
+		}
+	})
+}
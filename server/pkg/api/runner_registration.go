@@ -0,0 +1,142 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	lxd "github.com/lxc/lxd/client"
+	"github.com/lxc/lxd/shared/api"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/tracing"
+)
+
+const (
+	// setupUnitName is name of systemd unit that run setup script
+	setupUnitName = "myshoes-setup"
+	// registrationPollInterval is interval of reading log of setup script
+	registrationPollInterval = 2 * time.Second
+	// registrationLogLines is number of lines of log of setup script that are read at once
+	registrationLogLines = 200
+)
+
+var (
+	// errRunnerRegistrationFailed is error for failure marker is found in log of setup script
+	errRunnerRegistrationFailed = errors.New("runner registration is failed")
+	// errRunnerRegistrationTimeout is error for success marker is not found until timeout
+	errRunnerRegistrationTimeout = errors.New("runner registration is timed out")
+)
+
+// SetRunnerRegistrationWait set config of waiting for the runner to be registered
+func (s *ShoesLXDMultiServer) SetRunnerRegistrationWait(w config.RunnerRegistrationWait) {
+	s.registrationWait = w
+}
+
+// waitRunnerRegistration follow log of setup script until success marker or failure marker is found.
+func waitRunnerRegistration(ctx context.Context, host *lxdclient.LXDHost, instanceName string, w config.RunnerRegistrationWait, l *slog.Logger) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "waitRunnerRegistration", trace.WithAttributes(
+		attribute.String("lxd.host", host.HostConfig.LxdHost),
+		attribute.String("lxd.instance", instanceName),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	reqCtx := ctx
+	ctx, cancel := context.WithTimeout(ctx, w.Timeout)
+	defer cancel()
+
+	var log string
+	for {
+		log, err = readSetupLog(ctx, host, instanceName)
+		if err != nil {
+			l.Info("failed to read log of setup script, will retry", "err", err.Error())
+		} else {
+			registered, failure := matchRegistrationMarker(log, w)
+			if registered {
+				return nil
+			}
+			if failure != "" {
+				return fmt.Errorf("%w: found %q in log of %s: %s", errRunnerRegistrationFailed, failure, setupUnitName, lastLines(log, 10))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if reqCtx.Err() != nil {
+				return reqCtx.Err()
+			}
+			return fmt.Errorf("%w: %q is not found in log of %s after %s: %s", errRunnerRegistrationTimeout, w.SuccessMarker, setupUnitName, w.Timeout, lastLines(log, 10))
+		case <-time.After(registrationPollInterval):
+		}
+	}
+}
+
+// readSetupLog return tail of journal of setup script in instance
+func readSetupLog(ctx context.Context, host *lxdclient.LXDHost, instanceName string) (string, error) {
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return "", fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	stdout := &bufferCloser{Buffer: &bytes.Buffer{}}
+	stderr := &bufferCloser{Buffer: &bytes.Buffer{}}
+	dataDone := make(chan bool)
+
+	timer := metric.NewLXDAPITimer(ctx, host.HostConfig.LxdHost, "ExecInstance")
+	op, err := client.ExecInstance(instanceName, api.InstanceExecPost{
+		Command: setupLogCommand(registrationLogLines, false),
+	}, &lxd.InstanceExecArgs{
+		Stdout:   stdout,
+		Stderr:   stderr,
+		DataDone: dataDone,
+	})
+	timer.ObserveDuration(err)
+	if err != nil {
+		return "", fmt.Errorf("exec journalctl: %w", err)
+	}
+	if err := lxdclient.WaitOperation(ctx, op); err != nil {
+		return "", fmt.Errorf("waiting operation: %w", err)
+	}
+	select {
+	case <-dataDone:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	if op.Get().Metadata["return"] == nil || op.Get().Metadata["return"].(float64) != 0 {
+		return "", fmt.Errorf("journalctl is exited with %v: %s", op.Get().Metadata["return"], stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// matchRegistrationMarker return true if success marker is found in log, or found failure marker.
+// Success marker is preferred, because the runner may recover from an error (e.g. retrying to connect).
+func matchRegistrationMarker(log string, w config.RunnerRegistrationWait) (bool, string) {
+	if w.SuccessMarker != "" && strings.Contains(log, w.SuccessMarker) {
+		return true, ""
+	}
+	for _, m := range w.FailureMarkers {
+		if m != "" && strings.Contains(log, m) {
+			return false, m
+		}
+	}
+	return false, ""
+}
+
+// lastLines return last n lines of s
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+)
+
+func TestMatchRegistrationMarker(t *testing.T) {
+	w := config.RunnerRegistrationWait{
+		SuccessMarker:  "Listening for Jobs",
+		FailureMarkers: []string{"An error occurred:", "Failed to create a session"},
+	}
+
+	tests := []struct {
+		name           string
+		log            string
+		wantRegistered bool
+		wantFailure    string
+	}{
+		{
+			name: "starting",
+			log:  "Downloading runner\nConfiguring runner\n",
+		},
+		{
+			name:           "registered",
+			log:            "Configuring runner\n√ Connected to GitHub\n2024-01-01 00:00:00Z: Listening for Jobs\n",
+			wantRegistered: true,
+		},
+		{
+			name:        "failed",
+			log:         "Configuring runner\nAn error occurred: Not configured\n",
+			wantFailure: "An error occurred:",
+		},
+		{
+			name:           "recovered",
+			log:            "Failed to create a session. The runner registration has been deleted from the server\nListening for Jobs\n",
+			wantRegistered: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registered, failure := matchRegistrationMarker(tt.log, w)
+			if registered != tt.wantRegistered || failure != tt.wantFailure {
+				t.Errorf("matchRegistrationMarker() = (%t, %q), want (%t, %q)", registered, failure, tt.wantRegistered, tt.wantFailure)
+			}
+		})
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	if got := lastLines("a\nb\nc\n", 2); got != "b\nc" {
+		t.Errorf("lastLines() = %q, want %q", got, "b\nc")
+	}
+	if got := lastLines("a\n", 2); got != "a" {
+		t.Errorf("lastLines() = %q, want %q", got, "a")
+	}
+}
@@ -6,12 +6,21 @@ import (
 	"log/slog"
 	"net"
 	"sync"
+	"time"
 
 	myshoespb "github.com/whywaita/myshoes/api/proto.go"
 	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/auth"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/journal"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/prepare"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 )
 
@@ -24,44 +33,124 @@ type ShoesLXDMultiServer struct {
 	imageAliasMap   map[string]string
 
 	overCommitPercent uint64
+	// retryPolicy is policy of retrying allocation
+	retryPolicy config.RetryPolicy
+	// registrationWait is config of waiting for the runner to be registered
+	registrationWait config.RunnerRegistrationWait
+	// apiTokens is tokens that are allowed to call admin methods
+	apiTokens []auth.Token
+	// quarantine is config of keeping instances that are failed to set up
+	quarantine config.Quarantine
+	// tombstoneTTL is period of remembering deleted instances, 0 means disabled
+	tombstoneTTL time.Duration
+	// prepareSteps is steps that are executed in allocated instances before setup script
+	prepareSteps []prepare.Step
+	// preDeleteHook is config of command that is executed in instances before deleting
+	preDeleteHook config.PreDeleteHook
+	// reaper is config of reaping leaked instances that are allocated to runners
+	reaper config.Reaper
+
+	// store is state shared with other replicas
+	store store.Store
+	// journal is on-disk journal of allocation and deletion, nil if disabled
+	journal *journal.Journal
 
 	mu sync.Mutex
 }
 
 // New create gRPC server
-func New(hostConfigs *config.HostConfigMap, mapping map[myshoespb.ResourceType]config.Mapping, imageAliasMap map[string]string, overCommitPercent uint64) (*ShoesLXDMultiServer, error) {
+func New(hostConfigs *config.HostConfigMap, mapping map[myshoespb.ResourceType]config.Mapping, imageAliasMap map[string]string, overCommitPercent uint64, st store.Store, j *journal.Journal) (*ShoesLXDMultiServer, error) {
 	return &ShoesLXDMultiServer{
 		hostConfigs:       hostConfigs,
 		resourceMapping:   mapping,
 		overCommitPercent: overCommitPercent,
+		retryPolicy:       config.DefaultRetryPolicy,
+		prepareSteps:      prepare.DefaultSteps,
 		mu:                sync.Mutex{},
 		imageAliasMap:     imageAliasMap,
+		store:             st,
+		journal:           j,
 	}, nil
 }
 
-// Run run gRPC server
-func (s *ShoesLXDMultiServer) Run(listenPort int) error {
+// Run run gRPC server until ctx is canceled.
+// After ctx is canceled, new requests are rejected and in-flight requests are drained until shutdownTimeout.
+// Requests that are not finished by the deadline are canceled, and they roll back allocated instances.
+func (s *ShoesLXDMultiServer) Run(ctx context.Context, listenPort int, shutdownTimeout time.Duration) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", listenPort))
 	if err != nil {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 	slog.Info("start listen", "port", listenPort)
 
+	authorizer := auth.New(s.apiTokens, adminMethodScopes)
 	grpcServer := grpc.NewServer(
+		// Stop waits for canceled handlers to finish rolling back
+		grpc.WaitForHandlers(true),
+		// extract trace context from metadata, so spans become children of client's span
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.ChainUnaryInterceptor(
-			metric.LoggingUnaryServerInterceptor(),
+			metric.LoggingUnaryServerInterceptor(redactRequest),
 			metric.MetricsUnaryServerInterceptor(),
+			authorizer.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			authorizer.StreamServerInterceptor(),
 		),
 	)
 	pb.RegisterShoesLXDMultiServer(grpcServer, s)
 
-	if err := grpcServer.Serve(lis); err != nil {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- grpcServer.Serve(lis)
+	}()
+
+	select {
+	case err := <-errCh:
 		return fmt.Errorf("failed to serve gRPC: %w", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down gRPC server, draining in-flight requests", "timeout", shutdownTimeout.String())
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		slog.Info("all in-flight requests are finished")
+	case <-time.After(shutdownTimeout):
+		slog.Warn("in-flight requests are not finished by deadline, cancel them")
+		grpcServer.Stop()
+		<-stopped
 	}
 	return nil
 }
 
-func (s *ShoesLXDMultiServer) validateTargetHosts(ctx context.Context, targetHosts []string, logger *slog.Logger) ([]*lxdclient.LXDHost, error) {
+// excludeCordonedHosts return target hosts that are not cordoned.
+// If cordon state can't be loaded, the host is kept in target.
+func (s *ShoesLXDMultiServer) excludeCordonedHosts(ctx context.Context, targetHosts []string, logger *slog.Logger) []string {
+	var hosts []string
+	for _, target := range targetHosts {
+		l := logger.With("target", target)
+		cordoned, err := s.store.IsCordoned(ctx, target)
+		if err != nil {
+			l.Warn("failed to get cordon state of host", "err", err.Error())
+		}
+		if cordoned {
+			l.Info("ignore host in target, host is cordoned")
+			continue
+		}
+		hosts = append(hosts, target)
+	}
+	return hosts
+}
+
+func (s *ShoesLXDMultiServer) validateTargetHosts(ctx context.Context, targetHosts []string, logger *slog.Logger) (_ []*lxdclient.LXDHost, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "validateTargetHosts", trace.WithAttributes(attribute.StringSlice("lxd.target_hosts", targetHosts)))
+	defer func() { tracing.End(span, err) }()
+
 	var hostConfigs []config.HostConfig
 
 	for _, target := range targetHosts {
@@ -71,6 +160,10 @@ func (s *ShoesLXDMultiServer) validateTargetHosts(ctx context.Context, targetHos
 			l.Warn("ignore host in target", "err", err.Error())
 			continue
 		}
+		if !lxdclient.AllowRequest(target) {
+			l.Warn("ignore host in target, circuit breaker is open")
+			continue
+		}
 
 		hostConfigs = append(hostConfigs, *host)
 	}
@@ -3,20 +3,26 @@ package api
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"math/rand"
 	"strings"
 	"time"
 
 	lxd "github.com/lxc/lxd/client"
 	"github.com/lxc/lxd/shared/api"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/whywaita/myshoes/pkg/datastore"
 	"github.com/whywaita/myshoes/pkg/runner"
 	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/journal"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/tracing"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/webhook"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -33,121 +39,511 @@ func (bc *bufferCloser) Close() error {
 
 // AddInstance add instance to LXD server
 func (s *ShoesLXDMultiServer) AddInstance(ctx context.Context, req *pb.AddInstanceRequest) (*pb.AddInstanceResponse, error) {
-	slog.Info("AddInstance", "req", req)
-	l := slog.With("method", "AddInstance")
+	slog.Info("AddInstance", "req", redactAddInstanceRequest(req))
 	if _, err := runner.ToUUID(req.RunnerName); err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "failed to parse request name: %+v", err)
 	}
-	l = l.With("runnerName", req.RunnerName)
+	if err := validateSecretEnvironment(req.SecretEnvironment); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to validate secret environment: %+v", err)
+	}
+	return s.addInstanceWithJournal(ctx, req, nil, slog.With("method", "AddInstance", "runnerName", req.RunnerName))
+}
 
-	targetLXDHosts, err := s.validateTargetHosts(ctx, req.TargetHosts, l)
+// addInstanceWithJournal add instance, and record it to journal and webhook.
+// plan is result of shared host scan in batch request, nil if not in batch.
+func (s *ShoesLXDMultiServer) addInstanceWithJournal(ctx context.Context, req *pb.AddInstanceRequest, plan *batchPlan, l *slog.Logger) (*pb.AddInstanceResponse, error) {
+	jid := s.beginJournal(journal.Entry{
+		Operation:    journal.OperationAllocate,
+		RunnerName:   req.RunnerName,
+		ImageAlias:   s.parseImageAliasMap(req.OsVersion),
+		ResourceType: datastore.UnmarshalResourceTypePb(req.ResourceType).String(),
+	}, l)
+	resp, err := s.addInstance(ctx, req, jid, plan, l)
+	s.finishJournal(jid, err, l)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "failed to validate target hosts: %+v", err)
+		webhook.Emit(webhook.Event{
+			Type:         webhook.EventAllocationFailed,
+			RunnerName:   req.RunnerName,
+			ImageAlias:   s.parseImageAliasMap(req.OsVersion),
+			ResourceType: datastore.UnmarshalResourceTypePb(req.ResourceType).String(),
+			Message:      err.Error(),
+		})
 	}
+	return resp, err
+}
 
-	host, instanceName, err := s.addInstancePoolMode(ctx, targetLXDHosts, req, l)
+func (s *ShoesLXDMultiServer) addInstance(ctx context.Context, req *pb.AddInstanceRequest, jid uint64, plan *batchPlan, l *slog.Logger) (*pb.AddInstanceResponse, error) {
+	var targetLXDHosts []*lxdclient.LXDHost
+	if plan != nil {
+		targetLXDHosts = plan.targets
+	} else {
+		validateStartTime := time.Now()
+		var err error
+		targetLXDHosts, err = s.validateTargetHosts(ctx, s.excludeCordonedHosts(ctx, req.TargetHosts, l), l)
+		metric.ObserveAllocationPhase(ctx, metric.AllocationPhaseValidateTargetHosts, validateStartTime)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to validate target hosts: %+v", err)
+		}
+	}
+
+	host, instanceName, err := s.addInstancePoolMode(ctx, targetLXDHosts, req, jid, plan, l)
 	if err != nil {
 		return nil, err
 	}
-	timer := metric.NewLXDAPITimer(host.HostConfig.LxdHost, "GetInstance")
-	i, _, err := host.Client.GetInstance(instanceName) // this line needs to assurance, So I will get instance information again from API
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to acquire lxd client: %+v", err)
+	}
+	timer := metric.NewLXDAPITimer(ctx, host.HostConfig.LxdHost, "GetInstance")
+	i, _, err := client.GetInstance(instanceName) // this line needs to assurance, So I will get instance information again from API
 	timer.ObserveDuration(err)
+	release()
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to retrieve instance information: %+v", err)
 	}
 
 	l.Info("Success AddInstance", "host", host.HostConfig.LxdHost)
+	webhook.Emit(webhook.Event{
+		Type:         webhook.EventAllocationSucceeded,
+		RunnerName:   req.RunnerName,
+		Host:         host.HostConfig.LxdHost,
+		InstanceName: i.Name,
+		ImageAlias:   s.parseImageAliasMap(req.OsVersion),
+		ResourceType: datastore.UnmarshalResourceTypePb(req.ResourceType).String(),
+	})
 
 	return &pb.AddInstanceResponse{
-		CloudId:      i.Name,
+		CloudId:      formatCloudID(host.HostConfig.LxdHost, i.Name),
 		ShoesType:    "lxd",
 		IpAddress:    "",
 		ResourceType: req.ResourceType,
 	}, nil
 }
 
-func (s *ShoesLXDMultiServer) addInstancePoolMode(ctx context.Context, targets []*lxdclient.LXDHost, req *pb.AddInstanceRequest, _l *slog.Logger) (*lxdclient.LXDHost, string, error) {
-	host, instanceName, found := findInstanceByJob(ctx, targets, req.RunnerName, _l)
-	if !found {
-		resourceTypeName := datastore.UnmarshalResourceTypePb(req.ResourceType).String()
-		retried := 0
-		for {
-			var err error
-			host, instanceName, err = allocatePooledInstance(ctx, targets, resourceTypeName, s.parseImageAliasMap(req.OsVersion), s.overCommitPercent, req.RunnerName, _l)
-			if err != nil {
-				if retried < 10 {
-					retried++
-					_l.Info("AddInstance failed allocating instance", "retrying", retried, "err", err.Error())
-					time.Sleep(1 * time.Second)
-					continue
-				} else {
-					return nil, "", status.Errorf(codes.Internal, "can not allocate instance")
-				}
-			}
-			break
-		}
-	}
-	l := _l.With("host", host.HostConfig.LxdHost, "instance", instanceName)
-	l.Info("AddInstance for pool mode", "runnerName", instanceName)
-	client := host.Client
-	hostAddr := host.HostConfig.LxdHost
+// addInstancePoolMode allocate a pooled instance and set up it.
+// If setting up is failed, the instance is rolled back and the request is retried on another instance.
+func (s *ShoesLXDMultiServer) addInstancePoolMode(ctx context.Context, targets []*lxdclient.LXDHost, req *pb.AddInstanceRequest, jid uint64, plan *batchPlan, _l *slog.Logger) (*lxdclient.LXDHost, string, error) {
+	startTime := time.Now()
+	// instances that are failed to set up in this request
+	excluded := map[string]struct{}{}
+	for attempt := 1; ; attempt++ {
+		// result of batch scan is used only in first attempt, it is stale after that
+		var attemptPlan *batchPlan
+		if attempt == 1 {
+			attemptPlan = plan
+		}
+		host, instanceName, state, err := s.acquirePooledInstance(ctx, targets, req, attempt == 1, attemptPlan, excluded, _l)
+		if err != nil {
+			return nil, "", err
+		}
+		l := _l.With("host", host.HostConfig.LxdHost, "instance", instanceName, "attempt", attempt)
+		l.Info("AddInstance for pool mode", "runnerName", instanceName, "setupState", state)
+		s.updateJournal(jid, func(e *journal.Entry) {
+			e.Host = host.HostConfig.LxdHost
+			e.InstanceName = instanceName
+			e.AllocatedAt = time.Now()
+		}, l)
+
+		err = s.setupInstance(ctx, host, instanceName, req, state, l)
+		if err == nil {
+			return host, instanceName, nil
+		}
+		var serr *setupError
+		if !errors.As(err, &serr) {
+			return nil, "", err
+		}
+		if ctx.Err() != nil {
+			rollbackCanceledInstance(ctx, host, instanceName, l)
+			return nil, "", status.Errorf(status.FromContextError(ctx.Err()).Code(), "canceled while setting up instance: %+v", ctx.Err())
+		}
+
+		excluded[store.ReservationKey(host.HostConfig.LxdHost, instanceName)] = struct{}{}
+		rollback := s.rollbackSetupFailure(ctx, host, instanceName, req.RunnerName, serr, l)
+		metric.AllocationSetupFailuresTotal.WithLabelValues(serr.phase, rollback).Inc()
+		if attempt >= s.retryPolicy.MaxSetupAttempts || (s.retryPolicy.Budget > 0 && time.Since(startTime) > s.retryPolicy.Budget) {
+			l.Error("failed to set up instance, giving up", "phase", serr.phase, "rollback", rollback, "err", serr.err.Error())
+			return nil, "", serr.status()
+		}
+		l.Warn("failed to set up instance, retrying on another instance", "phase", serr.phase, "rollback", rollback, "err", serr.err.Error())
+	}
+}
 
-	err := unfreezeInstance(client, instanceName, hostAddr)
+// acquirePooledInstance return the instance that is allocated to the runner, and its setup state to resume from.
+// If findAllocated is true, the instance that is already allocated by previous request of same runner is returned if exists.
+// If plan is not nil, instances in plan are used instead of searching target hosts.
+func (s *ShoesLXDMultiServer) acquirePooledInstance(ctx context.Context, targets []*lxdclient.LXDHost, req *pb.AddInstanceRequest, findAllocated bool, plan *batchPlan, excluded map[string]struct{}, l *slog.Logger) (*lxdclient.LXDHost, string, string, error) {
+	resourceTypeName := datastore.UnmarshalResourceTypePb(req.ResourceType).String()
+	imageAlias := s.parseImageAliasMap(req.OsVersion)
+	if findAllocated {
+		var host *lxdclient.LXDHost
+		var instanceName, state string
+		var found bool
+		if plan != nil {
+			host, instanceName, state, found = resumeInstanceByJob(ctx, plan.allocated, req.RunnerName, l)
+		} else {
+			host, instanceName, state, found = findInstanceByJob(ctx, targets, req.RunnerName, l)
+		}
+		if found {
+			metric.PoolAllocationsTotal.WithLabelValues(imageAlias, resourceTypeName, metric.PoolResumed).Inc()
+			return host, instanceName, state, nil
+		}
+	}
+
+	allocateStartTime := time.Now()
+	var host *lxdclient.LXDHost
+	var instanceName string
+	var candidates []instance
+	if plan != nil {
+		candidates = plan.candidates
+	}
+	err := retry(ctx, s.retryPolicy, func() error {
+		var err error
+		host, instanceName, err = s.allocatePooledInstance(ctx, targets, resourceTypeName, imageAlias, s.overCommitPercent, req.RunnerName, candidates, excluded, l)
+		// candidates are stale after first try
+		candidates = nil
+		return err
+	}, func(attempt int, err error) {
+		metric.AllocationRetriesTotal.WithLabelValues(imageAlias, resourceTypeName).Inc()
+		l.Info("AddInstance failed allocating instance", "retrying", attempt, "err", err.Error())
+	})
+	metric.ObserveAllocationPhase(ctx, metric.AllocationPhaseAllocate, allocateStartTime)
 	if err != nil {
-		l.Error("failed to unfreeze instance, will delete...")
-		if err := recoverInvalidInstance(client, instanceName, hostAddr); err != nil {
-			l.Error("failed to delete invalid instance", "error", err.Error())
+		metric.PoolAllocationsTotal.WithLabelValues(imageAlias, resourceTypeName, metric.PoolMiss).Inc()
+		if ctx.Err() != nil {
+			return nil, "", "", status.Errorf(status.FromContextError(ctx.Err()).Code(), "canceled while allocating instance: %+v", ctx.Err())
+		}
+		if errors.Is(err, errPoolExhausted) {
+			webhook.Emit(webhook.Event{
+				Type:         webhook.EventPoolExhausted,
+				RunnerName:   req.RunnerName,
+				ImageAlias:   imageAlias,
+				ResourceType: resourceTypeName,
+				Message:      err.Error(),
+			})
+		}
+		return nil, "", "", status.Errorf(codes.Internal, "can not allocate instance")
+	}
+	metric.PoolAllocationsTotal.WithLabelValues(imageAlias, resourceTypeName, metric.PoolHit).Inc()
+	return host, instanceName, setupStateAllocated, nil
+}
+
+const (
+	// setupStateAllocated is state that the instance is allocated to the runner
+	setupStateAllocated = "allocated"
+	// setupStateUnfrozen is state that the instance is unfrozen
+	setupStateUnfrozen = "unfrozen"
+	// setupStatePrepared is state that preparation steps are completed
+	setupStatePrepared = "prepared"
+	// setupStateScriptCopied is state that setup script is copied into the instance
+	setupStateScriptCopied = "script_copied"
+	// setupStateStarted is state that setup script is started
+	setupStateStarted = "started"
+	// setupStateRollingBack is state that the instance is being deleted by canceled request, it must not be resumed
+	setupStateRollingBack = "rolling_back"
+)
+
+// setupError is error in setting up allocated instance, the request can be retried on another instance
+type setupError struct {
+	// phase is one of metric.AllocationPhase*
+	phase string
+	err   error
+	// prepared is true if preparation steps are executed in the instance, it can not be returned to the pool
+	prepared bool
+}
+
+func (e *setupError) Error() string {
+	return fmt.Sprintf("%s: %v", e.phase, e.err)
+}
+
+func (e *setupError) Unwrap() error {
+	return e.err
+}
+
+// status return gRPC error for the caller
+func (e *setupError) status() error {
+	switch e.phase {
+	case metric.AllocationPhaseUnfreeze:
+		return status.Errorf(codes.Internal, "unfreeze instance: %+v", e.err)
+	case metric.AllocationPhasePrepare:
+		return status.Errorf(codes.Internal, "failed to prepare instance: %+v", e.err)
+	case metric.AllocationPhaseCopySetupScript:
+		return status.Errorf(codes.Internal, "failed to copy setup script: %+v", e.err)
+	default:
+		return status.Errorf(codes.Internal, "failed to execute setup script: %+v", e.err)
+	}
+}
+
+// setupInstance unfreeze the allocated instance and run setup script in it, resuming from the step after state.
+// Each completed step is recorded to the instance, so the retry of request can resume from it.
+// It returns *setupError if the instance should be rolled back.
+func (s *ShoesLXDMultiServer) setupInstance(ctx context.Context, host *lxdclient.LXDHost, instanceName string, req *pb.AddInstanceRequest, state string, l *slog.Logger) error {
+	if state != setupStateAllocated {
+		l.Info("resuming setup of instance", "setupState", state)
+	}
+
+	if state == setupStateAllocated {
+		unfreezeStartTime := time.Now()
+		err := unfreezeInstance(ctx, host, instanceName)
+		metric.ObserveAllocationPhase(ctx, metric.AllocationPhaseUnfreeze, unfreezeStartTime)
+		if err != nil {
+			return &setupError{phase: metric.AllocationPhaseUnfreeze, err: err}
+		}
+		state = setupStateUnfrozen
+		s.recordSetupState(ctx, host, instanceName, state, l)
+	}
+
+	if state == setupStateUnfrozen {
+		prepareStartTime := time.Now()
+		err := s.prepareInstance(ctx, host, instanceName, req, l)
+		metric.ObserveAllocationPhase(ctx, metric.AllocationPhasePrepare, prepareStartTime)
+		if err != nil {
+			return &setupError{phase: metric.AllocationPhasePrepare, err: err}
+		}
+		state = setupStatePrepared
+		s.recordSetupState(ctx, host, instanceName, state, l)
+	}
+
+	// setup script is copied again if it is not started, because the script of retried request may be different (e.g. token of runner)
+	if state == setupStatePrepared || state == setupStateScriptCopied {
+		copyStartTime := time.Now()
+		err := copySetupScript(ctx, host, instanceName, req)
+		metric.ObserveAllocationPhase(ctx, metric.AllocationPhaseCopySetupScript, copyStartTime)
+		if err != nil {
+			return &setupError{phase: metric.AllocationPhaseCopySetupScript, err: err, prepared: len(s.prepareSteps) > 0}
+		}
+		s.recordSetupState(ctx, host, instanceName, setupStateScriptCopied, l)
+
+		execStartTime := time.Now()
+		err = execSetupScript(ctx, host, instanceName, req, l)
+		metric.ObserveAllocationPhase(ctx, metric.AllocationPhaseExecSetupScript, execStartTime)
+		if err != nil {
+			return &setupError{phase: metric.AllocationPhaseExecSetupScript, err: err}
 		}
-		return nil, "", status.Errorf(codes.Internal, "unfreeze instance: %+v", err)
+		state = setupStateStarted
+		s.recordSetupState(ctx, host, instanceName, state, l)
 	}
 
-	scriptFilename := fmt.Sprintf("/tmp/myshoes_setup_script.%d", rand.Int())
-	timer := metric.NewLXDAPITimer(hostAddr, "CreateInstanceFile")
-	err = client.CreateInstanceFile(instanceName, scriptFilename, lxd.InstanceFileArgs{
-		Content:   strings.NewReader(req.SetupScript),
-		Mode:      0744,
-		Type:      "file",
-		WriteMode: "overwrite",
+	if s.registrationWait.Enabled {
+		waitStartTime := time.Now()
+		err := waitRunnerRegistration(ctx, host, instanceName, s.registrationWait, l)
+		metric.ObserveAllocationPhase(ctx, metric.AllocationPhaseWaitRegistration, waitStartTime)
+		if err != nil {
+			// the runner may be registered to GitHub, so it is not retried on another instance
+			return s.handleRegistrationFailure(ctx, host, instanceName, req, err, l)
+		}
+	}
+	return nil
+}
+
+// recordSetupState record state to the instance. Failure is only logged, the request can be completed without it.
+func (s *ShoesLXDMultiServer) recordSetupState(ctx context.Context, host *lxdclient.LXDHost, instanceName, state string, l *slog.Logger) {
+	if err := recordSetupState(ctx, host, instanceName, state); err != nil {
+		l.Warn("failed to record setup state", "setupState", state, "err", err.Error())
+	}
+}
+
+// rollbackSetupFailure roll back the instance that is failed to set up, and return how it is rolled back (metric.SetupRollback*).
+// The instance is returned to the pool if nothing is executed in it, otherwise it is quarantined or deleted.
+func (s *ShoesLXDMultiServer) rollbackSetupFailure(ctx context.Context, host *lxdclient.LXDHost, instanceName, runnerName string, serr *setupError, l *slog.Logger) string {
+	ctx = context.WithoutCancel(ctx)
+
+	releasable, reason := setupRollbackAction(serr)
+	if releasable {
+		err := releaseInstance(ctx, host, instanceName, runnerName)
+		if err == nil {
+			l.Info("released instance to pool")
+			return metric.SetupRollbackReleased
+		}
+		l.Warn("failed to release instance, will delete...", "err", err.Error())
+	}
+
+	if reason != "" && s.quarantineFailedInstance(ctx, host, instanceName, reason, l) {
+		return metric.SetupRollbackQuarantined
+	}
+	if err := destroyInstance(ctx, host, instanceName); err != nil {
+		l.Error("failed to delete instance that is failed to set up", "error", err.Error())
+		return metric.SetupRollbackFailed
+	}
+	l.Info("deleted instance that is failed to set up")
+	return metric.SetupRollbackDestroyed
+}
+
+// setupRollbackAction return whether the instance that is failed to set up can be returned to the pool,
+// and reason of quarantine if it should be quarantined instead of deleted.
+func setupRollbackAction(serr *setupError) (bool, string) {
+	switch serr.phase {
+	case metric.AllocationPhaseCopySetupScript:
+		if !serr.prepared {
+			return true, ""
+		}
+		return false, QuarantineReasonCopyFailed
+	case metric.AllocationPhaseUnfreeze:
+		return false, QuarantineReasonUnfreezeFailed
+	case metric.AllocationPhasePrepare:
+		return false, QuarantineReasonPrepareFailed
+	case metric.AllocationPhaseExecSetupScript:
+		if errors.Is(serr.err, errSetupScriptFailed) {
+			return false, QuarantineReasonSetupScriptFailed
+		}
+	}
+	return false, ""
+}
+
+// handleRegistrationFailure quarantine or delete the instance that the runner is not registered, and return error for the caller.
+// The instance is not returned to myshoes, so it is never deleted by DeleteInstance.
+func (s *ShoesLXDMultiServer) handleRegistrationFailure(ctx context.Context, host *lxdclient.LXDHost, instanceName string, req *pb.AddInstanceRequest, err error, l *slog.Logger) error {
+	if ctx.Err() != nil {
+		// setup script is already started, so the instance is kept for retry of the request.
+		// It is deleted by the reaper if it is not resumed.
+		l.Warn("request is canceled while waiting for runner registration, keep instance to resume", "err", ctx.Err().Error())
+		return status.Errorf(status.FromContextError(ctx.Err()).Code(), "canceled while waiting for runner registration: %+v", ctx.Err())
+	}
+
+	webhook.Emit(webhook.Event{
+		Type:         webhook.EventRunnerRegistrationFailed,
+		RunnerName:   req.RunnerName,
+		Host:         host.HostConfig.LxdHost,
+		InstanceName: instanceName,
+		Message:      err.Error(),
 	})
-	timer.ObserveDuration(err)
+	if !s.quarantineFailedInstance(ctx, host, instanceName, QuarantineReasonRegistrationFailed, l) {
+		l.Error("runner is not registered, will delete...", "err", err.Error())
+		// setup script is started, so the instance is running
+		if err := destroyInstance(context.WithoutCancel(ctx), host, instanceName); err != nil {
+			l.Error("failed to delete instance that runner is not registered", "error", err.Error())
+		}
+	}
+
+	if errors.Is(err, errRunnerRegistrationTimeout) {
+		return status.Errorf(codes.DeadlineExceeded, "failed to wait for runner registration: %+v", err)
+	}
+	return status.Errorf(codes.Internal, "failed to wait for runner registration: %+v", err)
+}
+
+// rollbackCanceledInstance delete the instance if the request is canceled (e.g. server is shutting down) while setting up it.
+// The instance is not returned to the caller, so it will never be used.
+// It is marked as rolling back before deleting, so retry of the request does not resume it.
+func rollbackCanceledInstance(ctx context.Context, host *lxdclient.LXDHost, instanceName string, l *slog.Logger) {
+	if ctx.Err() == nil {
+		return
+	}
+	l.Warn("request is canceled while setting up instance, will delete...", "err", ctx.Err().Error())
+	ctx = context.WithoutCancel(ctx)
+	if err := recordSetupState(ctx, host, instanceName, setupStateRollingBack); err != nil {
+		l.Warn("failed to record setup state", "setupState", setupStateRollingBack, "err", err.Error())
+	}
+	// the instance is running if it is already unfrozen
+	if err := destroyInstance(ctx, host, instanceName); err != nil {
+		l.Error("failed to delete canceled instance", "error", err.Error())
+	}
+}
+
+// copySetupScript copy setup script and secret environment variables into instance, they are readable only by root.
+// They are removed if copying is failed, because the instance may be returned to the pool.
+func copySetupScript(ctx context.Context, host *lxdclient.LXDHost, instanceName string, req *pb.AddInstanceRequest) error {
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	hostAddr := host.HostConfig.LxdHost
+	for _, f := range []struct {
+		path    string
+		content string
+		mode    int
+	}{
+		{path: setupScriptPath, content: req.SetupScript, mode: 0700},
+		{path: setupEnvPath, content: setupEnvFile(req.SecretEnvironment), mode: 0600},
+	} {
+		timer := metric.NewLXDAPITimer(ctx, hostAddr, "CreateInstanceFile")
+		err = client.CreateInstanceFile(instanceName, f.path, lxd.InstanceFileArgs{
+			Content:   strings.NewReader(f.content),
+			UID:       0,
+			GID:       0,
+			Mode:      f.mode,
+			Type:      "file",
+			WriteMode: "overwrite",
+		})
+		timer.ObserveDuration(err)
+		if err != nil {
+			removeSetupScript(client, instanceName)
+			return err
+		}
+	}
+	return nil
+}
+
+// removeSetupScript remove setup script and secret environment variables that are not removed by the wrapper.
+// Errors are ignored, the files may be already removed.
+func removeSetupScript(client lxd.InstanceServer, instanceName string) {
+	for _, path := range []string{setupScriptPath, setupEnvPath} {
+		_ = client.DeleteInstanceFile(instanceName, path)
+	}
+}
+
+// errSetupScriptFailed is error that setup script is exited with non-zero
+var errSetupScriptFailed = errors.New("setup script is failed")
+
+// execSetupScript executes setup script in instance by systemd-run, and wait for starting it
+func execSetupScript(ctx context.Context, host *lxdclient.LXDHost, instanceName string, req *pb.AddInstanceRequest, l *slog.Logger) (err error) {
+	hostAddr := host.HostConfig.LxdHost
+	ctx, span := tracing.Tracer().Start(ctx, "execSetupScript", trace.WithAttributes(
+		attribute.String("lxd.host", hostAddr),
+		attribute.String("lxd.instance", instanceName),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	client, release, err := host.Acquire(ctx)
 	if err != nil {
-		return nil, "", status.Errorf(codes.Internal, "failed to copy setup script: %+v", err)
+		return fmt.Errorf("acquire lxd client: %w", err)
 	}
+	defer release()
+	defer func() {
+		if err != nil {
+			// the wrapper is not started if systemd-run is failed, secrets must not be left in the instance
+			removeSetupScript(client, instanceName)
+		}
+	}()
 
 	// Prepare stdout/stderr buffers for capturing exec output
 	stdout := &bufferCloser{Buffer: &bytes.Buffer{}}
 	stderr := &bufferCloser{Buffer: &bytes.Buffer{}}
 
-	timer = metric.NewLXDAPITimer(hostAddr, "ExecInstance")
+	timer := metric.NewLXDAPITimer(ctx, hostAddr, "ExecInstance")
 	op, err := client.ExecInstance(instanceName, api.InstanceExecPost{
-		Command: []string{
+		Command: append([]string{
 			"systemd-run",
 			"--unit", "myshoes-setup",
 			"--property", "After=multi-user.target",
 			"--property", "StandardOutput=journal+console",
-			"--property", fmt.Sprintf("ExecStartPre=/usr/bin/hostname %s", req.RunnerName), // set hostname inside instance. caution: we don't use hostnamectl because dbus is not response in environment of high load
-			"--property", fmt.Sprintf("ExecStartPre=/bin/sh -c 'echo 127.0.1.1 %s >> /etc/hosts'", req.RunnerName),
-			scriptFilename,
-		},
+		}, setupScriptCommand(req.SetupScript)...),
 	}, &lxd.InstanceExecArgs{
 		Stdout: stdout,
 		Stderr: stderr,
 	})
 	timer.ObserveDuration(err)
 	if err != nil {
-		return nil, "", status.Errorf(codes.Internal, "failed to execute setup script: %+v", err)
+		return fmt.Errorf("exec instance: %w", err)
 	}
-	if err := op.Wait(); err != nil {
-		return nil, "", status.Errorf(codes.Internal, "failed to wait executing setup script: %+v", err)
+	if err := lxdclient.WaitOperation(ctx, op); err != nil {
+		return fmt.Errorf("waiting operation: %w", err)
 	}
 
 	// Get command exit code, logging stdout/stderr if non-zero
 	if op.Get().Metadata["return"] == nil || op.Get().Metadata["return"].(float64) != 0 {
 		l.Error("Setup script failed", "stdout", stdout.String(), "stderr", stderr.String(), "exitCode", op.Get().Metadata["return"])
-		return nil, "", status.Errorf(codes.Internal, "failed to execute setup script: exit code %v", op.Get().Metadata["return"])
+		webhook.Emit(webhook.Event{
+			Type:         webhook.EventSetupScriptFailed,
+			RunnerName:   req.RunnerName,
+			Host:         hostAddr,
+			InstanceName: instanceName,
+			Message:      fmt.Sprintf("exit code %v", op.Get().Metadata["return"]),
+		})
+		return fmt.Errorf("%w: exit code %v", errSetupScriptFailed, op.Get().Metadata["return"])
 	}
 
-	return host, instanceName, nil
+	return nil
 }
 
 func (s *ShoesLXDMultiServer) parseImageAliasMap(version string) string {
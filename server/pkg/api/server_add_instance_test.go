@@ -0,0 +1,188 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/lxc/lxd/shared/api"
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+)
+
+func TestSetupError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantMessage string
+	}{
+		{
+			name:        "unfreeze",
+			err:         &setupError{phase: metric.AllocationPhaseUnfreeze, err: errors.New("unexpected instance state: Stopped")},
+			wantMessage: "unfreeze instance",
+		},
+		{
+			name:        "copy setup script",
+			err:         &setupError{phase: metric.AllocationPhaseCopySetupScript, err: errors.New("connection reset")},
+			wantMessage: "failed to copy setup script",
+		},
+		{
+			name:        "setup script exited with non-zero",
+			err:         &setupError{phase: metric.AllocationPhaseExecSetupScript, err: fmt.Errorf("%w: exit code 1", errSetupScriptFailed)},
+			wantMessage: "failed to execute setup script",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var serr *setupError
+			if !errors.As(tt.err, &serr) {
+				t.Fatalf("errors.As() = false, want true")
+			}
+			st, ok := status.FromError(serr.status())
+			if !ok {
+				t.Fatalf("status() is not gRPC status")
+			}
+			if st.Code() != codes.Internal {
+				t.Errorf("code = %v, want %v", st.Code(), codes.Internal)
+			}
+			if !strings.HasPrefix(st.Message(), tt.wantMessage) {
+				t.Errorf("message = %q, want prefix %q", st.Message(), tt.wantMessage)
+			}
+		})
+	}
+
+	err := error(&setupError{phase: metric.AllocationPhaseExecSetupScript, err: fmt.Errorf("%w: exit code 1", errSetupScriptFailed)})
+	if !errors.Is(err, errSetupScriptFailed) {
+		t.Errorf("errors.Is(err, errSetupScriptFailed) = false, want true")
+	}
+}
+
+func TestResumableSetupState(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      string
+		statusCode api.StatusCode
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "allocated",
+			state:      setupStateAllocated,
+			statusCode: api.Frozen,
+			want:       setupStateAllocated,
+		},
+		{
+			name:       "prepared",
+			state:      setupStatePrepared,
+			statusCode: api.Running,
+			want:       setupStatePrepared,
+		},
+		{
+			name:       "started",
+			state:      setupStateStarted,
+			statusCode: api.Running,
+			want:       setupStateStarted,
+		},
+		{
+			name:       "rolling back",
+			state:      setupStateRollingBack,
+			statusCode: api.Running,
+			wantErr:    true,
+		},
+		{
+			name:       "allocated by older version",
+			statusCode: api.Frozen,
+			want:       setupStateAllocated,
+		},
+		{
+			name:       "unfrozen by older version",
+			statusCode: api.Running,
+			wantErr:    true,
+		},
+		{
+			name:       "unknown state",
+			state:      "unknown",
+			statusCode: api.Running,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resumableSetupState(tt.state, tt.statusCode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resumableSetupState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resumableSetupState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetupRollbackAction(t *testing.T) {
+	tests := []struct {
+		name           string
+		serr           *setupError
+		wantReleasable bool
+		wantReason     string
+	}{
+		{
+			name:           "copy setup script without preparation",
+			serr:           &setupError{phase: metric.AllocationPhaseCopySetupScript, err: errors.New("connection reset")},
+			wantReleasable: true,
+		},
+		{
+			name:       "copy setup script after preparation",
+			serr:       &setupError{phase: metric.AllocationPhaseCopySetupScript, err: errors.New("connection reset"), prepared: true},
+			wantReason: QuarantineReasonCopyFailed,
+		},
+		{
+			name:       "prepare",
+			serr:       &setupError{phase: metric.AllocationPhasePrepare, err: errors.New("exit code 1")},
+			wantReason: QuarantineReasonPrepareFailed,
+		},
+		{
+			name:       "setup script exited with non-zero",
+			serr:       &setupError{phase: metric.AllocationPhaseExecSetupScript, err: fmt.Errorf("%w: exit code 1", errSetupScriptFailed)},
+			wantReason: QuarantineReasonSetupScriptFailed,
+		},
+		{
+			name: "failed to start setup script",
+			serr: &setupError{phase: metric.AllocationPhaseExecSetupScript, err: errors.New("connection reset")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			releasable, reason := setupRollbackAction(tt.serr)
+			if releasable != tt.wantReleasable {
+				t.Errorf("releasable = %v, want %v", releasable, tt.wantReleasable)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestHandleRegistrationFailure_Delete(t *testing.T) {
+	host, fake := newFakeLXDHost(t, map[string]api.StatusCode{"instance-1": api.Running})
+
+	// quarantine is disabled, so the running instance is deleted
+	s := &ShoesLXDMultiServer{}
+	err := s.handleRegistrationFailure(context.Background(), host, "instance-1", &pb.AddInstanceRequest{RunnerName: "runner-1"}, errRunnerRegistrationTimeout, slog.Default())
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("handleRegistrationFailure() = %v, want %v", err, codes.DeadlineExceeded)
+	}
+	if s, ok := fake.status("instance-1"); ok {
+		t.Errorf("instance is not deleted, status is %s", s)
+	}
+}
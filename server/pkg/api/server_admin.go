@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/auth"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+)
+
+// adminMethodScopes is scopes that are required by methods for operating hosts and debugging instances.
+// Methods that are not in it (AddInstance, DeleteInstance and their variants) are called by myshoes without token.
+var adminMethodScopes = map[string]auth.Scope{
+	pb.ShoesLXDMulti_CordonHost_FullMethodName:                 auth.ScopeAdmin,
+	pb.ShoesLXDMulti_UncordonHost_FullMethodName:               auth.ScopeAdmin,
+	pb.ShoesLXDMulti_ListJournal_FullMethodName:                auth.ScopeAdmin,
+	pb.ShoesLXDMulti_GetSetupLog_FullMethodName:                auth.ScopeAdmin,
+	pb.ShoesLXDMulti_ExecInstance_FullMethodName:               auth.ScopeAdmin,
+	pb.ShoesLXDMulti_PullFile_FullMethodName:                   auth.ScopeAdmin,
+	pb.ShoesLXDMulti_ListQuarantinedInstances_FullMethodName:   auth.ScopeAdmin,
+	pb.ShoesLXDMulti_ReleaseQuarantinedInstance_FullMethodName: auth.ScopeAdmin,
+}
+
+// SetAPITokens set tokens that are allowed to call admin methods.
+// Admin methods are denied if no token is set.
+func (s *ShoesLXDMultiServer) SetAPITokens(tokens []auth.Token) {
+	s.apiTokens = tokens
+}
+
+// ExecInstance run command in instance, and stream its stdout and stderr
+func (s *ShoesLXDMultiServer) ExecInstance(req *pb.ExecInstanceRequest, stream pb.ShoesLXDMulti_ExecInstanceServer) error {
+	ctx := stream.Context()
+	l := slog.With("method", "ExecInstance", "instanceName", req.CloudId, "caller", auth.CallerFromContext(ctx))
+	// command is recorded for audit
+	l.Info("ExecInstance", "command", req.Command)
+
+	if len(req.Command) == 0 {
+		return status.Errorf(codes.InvalidArgument, "command is required")
+	}
+	if req.TimeoutSec < 0 {
+		return status.Errorf(codes.InvalidArgument, "timeout_sec must not be negative")
+	}
+	host, instanceName, err := s.locateInstance(ctx, req.CloudId, req.TargetHosts, l)
+	if err != nil {
+		return err
+	}
+
+	execCtx := ctx
+	if req.TimeoutSec > 0 {
+		var cancel context.CancelFunc
+		execCtx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutSec)*time.Second)
+		defer cancel()
+	}
+
+	sender := &streamSender{}
+	defer sender.Close()
+	stdout := sender.writer(func(data []byte) error {
+		return stream.Send(&pb.ExecInstanceResponse{Output: &pb.ExecInstanceResponse_Stdout{Stdout: data}})
+	})
+	stderr := sender.writer(func(data []byte) error {
+		return stream.Send(&pb.ExecInstanceResponse{Output: &pb.ExecInstanceResponse_Stderr{Stderr: data}})
+	})
+
+	code, err := execStream(execCtx, host, instanceName, api.InstanceExecPost{
+		Command:     req.Command,
+		Environment: req.Environment,
+	}, stdout, stderr, l)
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if execCtx.Err() != nil {
+			return status.Errorf(codes.DeadlineExceeded, "command is not finished in %d seconds", req.TimeoutSec)
+		}
+		return status.Errorf(codes.Internal, "failed to exec command: %+v", err)
+	}
+	l.Info("command is finished", "exitCode", code)
+
+	if err := sender.send(func() error {
+		return stream.Send(&pb.ExecInstanceResponse{Output: &pb.ExecInstanceResponse_ExitCode{ExitCode: int32(code)}})
+	}); err != nil {
+		return status.Errorf(codes.Internal, "failed to send exit code: %+v", err)
+	}
+	return nil
+}
+
+// PullFile return file in instance. If path is directory, names of entries are returned.
+func (s *ShoesLXDMultiServer) PullFile(req *pb.PullFileRequest, stream pb.ShoesLXDMulti_PullFileServer) error {
+	ctx := stream.Context()
+	l := slog.With("method", "PullFile", "instanceName", req.CloudId, "caller", auth.CallerFromContext(ctx))
+	l.Info("PullFile", "path", req.Path)
+
+	if !path.IsAbs(req.Path) {
+		return status.Errorf(codes.InvalidArgument, "path must be absolute")
+	}
+	host, instanceName, err := s.locateInstance(ctx, req.CloudId, req.TargetHosts, l)
+	if err != nil {
+		return err
+	}
+
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to acquire lxd client: %+v", err)
+	}
+	defer release()
+
+	timer := metric.NewLXDAPITimer(ctx, host.HostConfig.LxdHost, "GetInstanceFile")
+	r, resp, err := client.GetInstanceFile(instanceName, path.Clean(req.Path))
+	timer.ObserveDuration(err)
+	if err != nil {
+		if _, ok := api.StatusErrorMatch(err, http.StatusNotFound); ok {
+			return status.Errorf(codes.NotFound, "%s is not found in instance", req.Path)
+		}
+		return status.Errorf(codes.Internal, "failed to get file: %+v", err)
+	}
+
+	if resp.Type == "directory" {
+		if err := stream.Send(&pb.PullFileResponse{Entries: resp.Entries}); err != nil {
+			return status.Errorf(codes.Internal, "failed to send entries: %+v", err)
+		}
+		return nil
+	}
+	defer r.Close()
+
+	sender := &streamSender{}
+	defer sender.Close()
+	w := sender.writer(func(data []byte) error {
+		return stream.Send(&pb.PullFileResponse{Data: data})
+	})
+	if _, err := io.Copy(w, r); err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return status.Errorf(codes.Internal, "failed to send file: %+v", err)
+	}
+	return nil
+}
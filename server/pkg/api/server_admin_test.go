@@ -0,0 +1,40 @@
+package api
+
+import (
+	"testing"
+
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+)
+
+// TestAdminMethodScopes ensures that methods other than called by myshoes require token
+func TestAdminMethodScopes(t *testing.T) {
+	public := map[string]struct{}{
+		"AddInstance":        {},
+		"DeleteInstance":     {},
+		"DeleteByRunnerName": {},
+		"AddInstances":       {},
+		"DeleteInstances":    {},
+	}
+
+	var methods []string
+	for _, m := range pb.ShoesLXDMulti_ServiceDesc.Methods {
+		methods = append(methods, m.MethodName)
+	}
+	for _, s := range pb.ShoesLXDMulti_ServiceDesc.Streams {
+		methods = append(methods, s.StreamName)
+	}
+
+	for _, m := range methods {
+		fullMethod := "/" + pb.ShoesLXDMulti_ServiceDesc.ServiceName + "/" + m
+		_, scoped := adminMethodScopes[fullMethod]
+		if _, ok := public[m]; ok {
+			if scoped {
+				t.Errorf("%s requires token, but it is called by myshoes", m)
+			}
+			continue
+		}
+		if !scoped {
+			t.Errorf("%s does not require token", m)
+		}
+	}
+}
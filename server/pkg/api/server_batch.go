@@ -0,0 +1,259 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/runner"
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+)
+
+// maxBatchSize is max number of items in a batch request
+const maxBatchSize = 100
+
+// batchPlan is result of shared host scan for an item in batch request
+type batchPlan struct {
+	// targets is validated target hosts
+	targets []*lxdclient.LXDHost
+	// allocated is instances that are allocated to the runner by previous request
+	allocated []instance
+	// candidates is pooled instances that are tried first, not nil
+	candidates []instance
+}
+
+// AddInstances add instances in one request
+func (s *ShoesLXDMultiServer) AddInstances(ctx context.Context, req *pb.AddInstancesRequest) (*pb.AddInstancesResponse, error) {
+	slog.Info("AddInstances", "count", len(req.Instances))
+	l := slog.With("method", "AddInstances")
+	if len(req.Instances) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "number of instances must be less than or equal to %d", maxBatchSize)
+	}
+
+	results := make([]*pb.AddInstancesResult, len(req.Instances))
+	var valid []int
+	// runner names in the batch, same runner must not be set up twice
+	runnerNames := map[string]struct{}{}
+	for idx, r := range req.Instances {
+		results[idx] = &pb.AddInstancesResult{RunnerName: r.RunnerName}
+		if _, err := runner.ToUUID(r.RunnerName); err != nil {
+			results[idx].Error = batchError(status.Errorf(codes.InvalidArgument, "failed to parse request name: %+v", err))
+			continue
+		}
+		if _, ok := runnerNames[r.RunnerName]; ok {
+			results[idx].Error = batchError(status.Errorf(codes.InvalidArgument, "runner name %s is duplicated in the request", r.RunnerName))
+			continue
+		}
+		runnerNames[r.RunnerName] = struct{}{}
+		if err := validateSecretEnvironment(r.SecretEnvironment); err != nil {
+			results[idx].Error = batchError(status.Errorf(codes.InvalidArgument, "failed to validate secret environment: %+v", err))
+			continue
+		}
+		valid = append(valid, idx)
+	}
+
+	plans, errs := s.planAddInstances(ctx, req.Instances, valid, l)
+
+	var wg sync.WaitGroup
+	for _, idx := range valid {
+		if errs[idx] != nil {
+			results[idx].Error = batchError(errs[idx])
+			continue
+		}
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			r := req.Instances[idx]
+			resp, err := s.addInstanceWithJournal(ctx, r, plans[idx], l.With("runnerName", r.RunnerName))
+			if err != nil {
+				results[idx].Error = batchError(err)
+				return
+			}
+			results[idx].Instance = resp
+		}(idx)
+	}
+	wg.Wait()
+
+	return &pb.AddInstancesResponse{Results: results}, nil
+}
+
+// planAddInstances scan target hosts once for each group of items that have same target hosts, and return plan of each item.
+// Items that are failed to plan have error in same index.
+func (s *ShoesLXDMultiServer) planAddInstances(ctx context.Context, reqs []*pb.AddInstanceRequest, indexes []int, l *slog.Logger) ([]*batchPlan, []error) {
+	plans := make([]*batchPlan, len(reqs))
+	errs := make([]error, len(reqs))
+
+	groups := map[string][]int{}
+	var keys []string
+	for _, idx := range indexes {
+		key := targetHostsKey(reqs[idx].TargetHosts)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], idx)
+	}
+
+	for _, key := range keys {
+		group := groups[key]
+		l := l.With("targetHosts", key)
+
+		validateStartTime := time.Now()
+		targets, err := s.validateTargetHosts(ctx, s.excludeCordonedHosts(ctx, reqs[group[0]].TargetHosts, l), l)
+		metric.ObserveAllocationPhase(ctx, metric.AllocationPhaseValidateTargetHosts, validateStartTime)
+		if err != nil {
+			for _, idx := range group {
+				errs[idx] = status.Errorf(codes.InvalidArgument, "failed to validate target hosts: %+v", err)
+			}
+			continue
+		}
+
+		runnerNames := map[string]struct{}{}
+		for _, idx := range group {
+			runnerNames[reqs[idx].RunnerName] = struct{}{}
+		}
+		allocated := map[string][]instance{}
+		for _, i := range findInstances(ctx, targets, func(i api.Instance) bool {
+			_, ok := runnerNames[i.Config[lxdclient.ConfigKeyRunnerName]]
+			return ok && isAllocatedInstance(i)
+		}, 0, l) {
+			runnerName := i.Config[lxdclient.ConfigKeyRunnerName]
+			allocated[runnerName] = append(allocated[runnerName], i)
+		}
+
+		findStartTime := time.Now()
+		pooled := findInstances(ctx, targets, func(i api.Instance) bool {
+			return isPooledInstance(i, i.Config[lxdclient.ConfigKeyResourceType], i.Config[lxdclient.ConfigKeyImageAlias])
+		}, s.overCommitPercent, l)
+		metric.ObserveAllocationPhase(ctx, metric.AllocationPhaseFindInstances, findStartTime)
+
+		// offset of each flavor, items of same flavor start from different candidates to avoid conflicting
+		offsets := map[string]int{}
+		candidates := map[string][]instance{}
+		for _, idx := range group {
+			resourceType := datastore.UnmarshalResourceTypePb(reqs[idx].ResourceType).String()
+			imageAlias := s.parseImageAliasMap(reqs[idx].OsVersion)
+			flavor := resourceType + "/" + imageAlias
+			if _, ok := candidates[flavor]; !ok {
+				candidates[flavor] = spreadCandidates(slices.DeleteFunc(slices.Clone(pooled), func(i instance) bool {
+					return i.Config[lxdclient.ConfigKeyResourceType] != resourceType || i.Config[lxdclient.ConfigKeyImageAlias] != imageAlias
+				}))
+			}
+
+			plans[idx] = &batchPlan{
+				targets:    targets,
+				allocated:  allocated[reqs[idx].RunnerName],
+				candidates: rotateCandidates(candidates[flavor], offsets[flavor]),
+			}
+			offsets[flavor]++
+		}
+	}
+	return plans, errs
+}
+
+// targetHostsKey return key of target hosts that does not depend on order
+func targetHostsKey(targetHosts []string) string {
+	hosts := slices.Clone(targetHosts)
+	slices.Sort(hosts)
+	return strings.Join(slices.Compact(hosts), ",")
+}
+
+// spreadCandidates reorder candidates to round robin of hosts, keeping order of hosts.
+// findInstances sort hosts by over commit, so allocations in batch are spread from the least loaded host.
+func spreadCandidates(candidates []instance) []instance {
+	var hosts []string
+	byHost := map[string][]instance{}
+	for _, i := range candidates {
+		host := i.Host.HostConfig.LxdHost
+		if _, ok := byHost[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		byHost[host] = append(byHost[host], i)
+	}
+
+	spread := make([]instance, 0, len(candidates))
+	for n := 0; len(spread) < len(candidates); n++ {
+		for _, host := range hosts {
+			if n < len(byHost[host]) {
+				spread = append(spread, byHost[host][n])
+			}
+		}
+	}
+	return spread
+}
+
+// rotateCandidates return candidates that start from offset, not nil
+func rotateCandidates(candidates []instance, offset int) []instance {
+	if len(candidates) == 0 {
+		return []instance{}
+	}
+	offset %= len(candidates)
+	return append(slices.Clone(candidates[offset:]), candidates[:offset]...)
+}
+
+// DeleteInstances delete instances in one request
+func (s *ShoesLXDMultiServer) DeleteInstances(ctx context.Context, req *pb.DeleteInstancesRequest) (*pb.DeleteInstancesResponse, error) {
+	slog.Info("DeleteInstances", "count", len(req.Instances))
+	l := slog.With("method", "DeleteInstances")
+	if len(req.Instances) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "number of instances must be less than or equal to %d", maxBatchSize)
+	}
+
+	results := make([]*pb.DeleteInstancesResult, len(req.Instances))
+	// validated target hosts by key of target hosts
+	targets := map[string][]*lxdclient.LXDHost{}
+	targetErrs := map[string]error{}
+	for idx, r := range req.Instances {
+		results[idx] = &pb.DeleteInstancesResult{CloudId: r.CloudId}
+		key := targetHostsKey(r.TargetHosts)
+		if _, ok := targets[key]; ok {
+			continue
+		}
+		if _, ok := targetErrs[key]; ok {
+			continue
+		}
+		hosts, err := s.validateTargetHosts(ctx, r.TargetHosts, l)
+		if err != nil {
+			targetErrs[key] = status.Errorf(codes.InvalidArgument, "failed to validate target hosts: %+v", err)
+			continue
+		}
+		targets[key] = hosts
+	}
+
+	var wg sync.WaitGroup
+	for idx, r := range req.Instances {
+		key := targetHostsKey(r.TargetHosts)
+		if err, ok := targetErrs[key]; ok {
+			results[idx].Error = batchError(err)
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, r *pb.DeleteInstanceRequest) {
+			defer wg.Done()
+			if _, err := s.deleteInstanceWithJournal(ctx, r, "", targets[key], l.With("instanceName", r.CloudId)); err != nil {
+				results[idx].Error = batchError(err)
+			}
+		}(idx, r)
+	}
+	wg.Wait()
+
+	return &pb.DeleteInstancesResponse{Results: results}, nil
+}
+
+// batchError convert error to error of item in batch response
+func batchError(err error) *pb.BatchError {
+	st := status.Convert(err)
+	return &pb.BatchError{
+		Code:    int32(st.Code()),
+		Message: st.Message(),
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"google.golang.org/grpc/codes"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+)
+
+func instanceNames(s []instance) []string {
+	var names []string
+	for _, i := range s {
+		names = append(names, i.InstanceName)
+	}
+	return names
+}
+
+func TestSpreadCandidates(t *testing.T) {
+	hostA := &lxdclient.LXDHost{HostConfig: config.HostConfig{LxdHost: "test-host-a"}}
+	hostB := &lxdclient.LXDHost{HostConfig: config.HostConfig{LxdHost: "test-host-b"}}
+	hostC := &lxdclient.LXDHost{HostConfig: config.HostConfig{LxdHost: "test-host-c"}}
+
+	// hosts are sorted by over commit in findInstances
+	candidates := []instance{
+		{Host: hostA, InstanceName: "a1"},
+		{Host: hostA, InstanceName: "a2"},
+		{Host: hostA, InstanceName: "a3"},
+		{Host: hostB, InstanceName: "b1"},
+		{Host: hostC, InstanceName: "c1"},
+		{Host: hostC, InstanceName: "c2"},
+	}
+	got := instanceNames(spreadCandidates(candidates))
+	want := []string{"a1", "b1", "c1", "a2", "c2", "a3"}
+	if !slices.Equal(got, want) {
+		t.Errorf("spreadCandidates() = %v, want %v", got, want)
+	}
+
+	if got := spreadCandidates(nil); got == nil || len(got) != 0 {
+		t.Errorf("spreadCandidates(nil) = %v, want empty", got)
+	}
+}
+
+func TestRotateCandidates(t *testing.T) {
+	hostA := &lxdclient.LXDHost{HostConfig: config.HostConfig{LxdHost: "test-host-a"}}
+	candidates := []instance{
+		{Host: hostA, InstanceName: "a1"},
+		{Host: hostA, InstanceName: "a2"},
+		{Host: hostA, InstanceName: "a3"},
+	}
+
+	tests := []struct {
+		offset int
+		want   []string
+	}{
+		{offset: 0, want: []string{"a1", "a2", "a3"}},
+		{offset: 1, want: []string{"a2", "a3", "a1"}},
+		{offset: 4, want: []string{"a2", "a3", "a1"}},
+	}
+	for _, tt := range tests {
+		if got := instanceNames(rotateCandidates(candidates, tt.offset)); !slices.Equal(got, tt.want) {
+			t.Errorf("rotateCandidates(%d) = %v, want %v", tt.offset, got, tt.want)
+		}
+	}
+	if !slices.Equal(instanceNames(candidates), []string{"a1", "a2", "a3"}) {
+		t.Errorf("rotateCandidates() modified candidates: %v", instanceNames(candidates))
+	}
+
+	// empty candidates must not be nil, nil means searching target hosts in allocatePooledInstance
+	if got := rotateCandidates(nil, 1); got == nil {
+		t.Errorf("rotateCandidates(nil) = nil, want empty")
+	}
+}
+
+func TestTargetHostsKey(t *testing.T) {
+	if targetHostsKey([]string{"b", "a", "b"}) != targetHostsKey([]string{"a", "b"}) {
+		t.Errorf("targetHostsKey() depends on order or duplication")
+	}
+	if targetHostsKey([]string{"a"}) == targetHostsKey([]string{"a", "b"}) {
+		t.Errorf("targetHostsKey() is same for different hosts")
+	}
+}
+
+func TestAddInstances_DuplicatedRunnerName(t *testing.T) {
+	s, err := New(config.NewHostConfigMap(), nil, nil, 100, store.NewMemory(), nil)
+	if err != nil {
+		t.Fatalf("failed to create server: %+v", err)
+	}
+
+	runnerName := "myshoes-00000000-0000-0000-0000-000000000000"
+	resp, err := s.AddInstances(context.Background(), &pb.AddInstancesRequest{
+		Instances: []*pb.AddInstanceRequest{
+			{RunnerName: runnerName},
+			{RunnerName: runnerName},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to add instances: %+v", err)
+	}
+	if len(resp.Results) != 2 {
+		t.Fatalf("len(Results) = %d, want 2", len(resp.Results))
+	}
+	// first item is processed (failed by no target hosts in this test)
+	if got := resp.Results[0].GetError().GetMessage(); strings.Contains(got, "duplicated") {
+		t.Errorf("first item is rejected as duplicated: %s", got)
+	}
+	if got := resp.Results[1].GetError(); got.GetCode() != int32(codes.InvalidArgument) || !strings.Contains(got.GetMessage(), "duplicated") {
+		t.Errorf("error of duplicated item = %v, want InvalidArgument for duplication", got)
+	}
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CordonHost mark host as unschedulable. The state is shared with other replicas.
+func (s *ShoesLXDMultiServer) CordonHost(ctx context.Context, req *pb.CordonHostRequest) (*pb.CordonHostResponse, error) {
+	slog.Info("CordonHost", "req", req)
+	if err := s.setCordon(ctx, req.Host, true); err != nil {
+		return nil, err
+	}
+	return &pb.CordonHostResponse{}, nil
+}
+
+// UncordonHost mark host as schedulable
+func (s *ShoesLXDMultiServer) UncordonHost(ctx context.Context, req *pb.UncordonHostRequest) (*pb.UncordonHostResponse, error) {
+	slog.Info("UncordonHost", "req", req)
+	if err := s.setCordon(ctx, req.Host, false); err != nil {
+		return nil, err
+	}
+	return &pb.UncordonHostResponse{}, nil
+}
+
+func (s *ShoesLXDMultiServer) setCordon(ctx context.Context, host string, cordoned bool) error {
+	if _, err := s.hostConfigs.Load(host); err != nil {
+		return status.Errorf(codes.NotFound, "host %q is not found: %+v", host, err)
+	}
+	if err := s.store.SetCordon(ctx, host, cordoned); err != nil {
+		return status.Errorf(codes.Internal, "failed to set cordon state: %+v", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+)
+
+func TestCordonHost(t *testing.T) {
+	ctx := context.Background()
+	hostConfigs := config.NewHostConfigMap()
+	hostConfigs.Store("host-a", config.HostConfig{LxdHost: "host-a"})
+	hostConfigs.Store("host-b", config.HostConfig{LxdHost: "host-b"})
+	s, err := New(hostConfigs, nil, nil, 100, store.NewMemory(), nil)
+	if err != nil {
+		t.Fatalf("failed to create server: %+v", err)
+	}
+
+	if _, err := s.CordonHost(ctx, &pb.CordonHostRequest{Host: "host-a"}); err != nil {
+		t.Fatalf("failed to cordon host: %+v", err)
+	}
+	got := s.excludeCordonedHosts(ctx, []string{"host-a", "host-b"}, slog.Default())
+	if want := []string{"host-b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("excludeCordonedHosts() = %v, want %v", got, want)
+	}
+
+	if _, err := s.UncordonHost(ctx, &pb.UncordonHostRequest{Host: "host-a"}); err != nil {
+		t.Fatalf("failed to uncordon host: %+v", err)
+	}
+	got = s.excludeCordonedHosts(ctx, []string{"host-a", "host-b"}, slog.Default())
+	if want := []string{"host-a", "host-b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("excludeCordonedHosts() = %v, want %v", got, want)
+	}
+
+	if _, err := s.CordonHost(ctx, &pb.CordonHostRequest{Host: "unknown"}); status.Code(err) != codes.NotFound {
+		t.Errorf("CordonHost() for unknown host returned %v, want NotFound", err)
+	}
+}
@@ -3,12 +3,21 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/lxc/lxd/shared/api"
+	"github.com/whywaita/myshoes/pkg/runner"
 	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/journal"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/webhook"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -16,55 +25,230 @@ import (
 // DeleteInstance delete instance to LXD server
 func (s *ShoesLXDMultiServer) DeleteInstance(ctx context.Context, req *pb.DeleteInstanceRequest) (*pb.DeleteInstanceResponse, error) {
 	slog.Info("DeleteInstance", "req", req)
-	l := slog.With("method", "DeleteInstance")
-	instanceName := req.CloudId
-	l = l.With("instanceName", instanceName)
-	targetLXDHosts, err := s.validateTargetHosts(ctx, req.TargetHosts, l)
-	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "failed to validate target hosts: %+v", err)
+	return s.deleteInstanceWithJournal(ctx, req, "", nil, slog.With("method", "DeleteInstance", "instanceName", req.CloudId))
+}
+
+// deleteInstanceWithJournal delete instance, and record it to journal.
+// runnerName is found from journal if empty.
+// targetLXDHosts is validated target hosts (e.g. in batch request), nil if not validated yet.
+func (s *ShoesLXDMultiServer) deleteInstanceWithJournal(ctx context.Context, req *pb.DeleteInstanceRequest, runnerName string, targetLXDHosts []*lxdclient.LXDHost, l *slog.Logger) (*pb.DeleteInstanceResponse, error) {
+	instanceName, _ := parseCloudID(req.CloudId)
+	if runnerName == "" {
+		runnerName = s.runnerNameFromJournal(instanceName, l)
+	}
+	jid := s.beginJournal(journal.Entry{
+		Operation:    journal.OperationDelete,
+		RunnerName:   runnerName,
+		InstanceName: instanceName,
+	}, l)
+	resp, err := s.deleteInstance(ctx, req, runnerName, jid, targetLXDHosts, l)
+	s.finishJournal(jid, err, l)
+	return resp, err
+}
+
+func (s *ShoesLXDMultiServer) deleteInstance(ctx context.Context, req *pb.DeleteInstanceRequest, runnerName string, jid uint64, targetLXDHosts []*lxdclient.LXDHost, l *slog.Logger) (*pb.DeleteInstanceResponse, error) {
+	if targetLXDHosts == nil {
+		var err error
+		targetLXDHosts, err = s.validateTargetHosts(ctx, req.TargetHosts, l)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to validate target hosts: %+v", err)
+		}
 	}
 
-	host, err := s.isExistInstance(targetLXDHosts, instanceName, l)
+	host, instanceName, err := s.findInstanceByCloudID(ctx, targetLXDHosts, req.CloudId, l)
 	if err != nil {
-		switch {
-		case errors.Is(err, ErrInstanceIsNotFound):
-			return nil, status.Errorf(codes.NotFound, "failed to found worker that has %s", instanceName)
-		default:
-			return nil, status.Errorf(codes.Internal, "failed to found worker that has %s", instanceName)
+		if errors.Is(err, ErrInstanceIsNotFound) {
+			if s.isRecentlyDeleted(ctx, tombstoneKeyCloudID(req.CloudId), l) {
+				// retry of deletion that is succeeded (e.g. the caller is timed out)
+				l.Info("instance is already deleted")
+				return &pb.DeleteInstanceResponse{}, nil
+			}
 		}
+		return nil, findInstanceStatus(err, req.CloudId)
 	}
 
 	l = l.With("host", host.HostConfig.LxdHost)
+	s.updateJournal(jid, func(e *journal.Entry) {
+		e.Host = host.HostConfig.LxdHost
+	}, l)
+	// tombstone is saved before deleting, because deletion can be completed in LXD after the caller is timed out.
+	// It is used only if the instance is not found, so it is harmless even if the deletion is failed.
+	cloudID := formatCloudID(host.HostConfig.LxdHost, instanceName)
+	s.saveTombstone(ctx, tombstoneKeyCloudID(cloudID), runnerName, l)
+	if req.CloudId != cloudID {
+		// older cloud_id is instance name only, it is remembered as is for retry of the caller
+		s.saveTombstone(ctx, tombstoneKeyCloudID(req.CloudId), runnerName, l)
+	}
+	if runnerName != "" {
+		s.saveTombstone(ctx, tombstoneKeyRunnerName(runnerName), cloudID, l)
+	}
+
+	force := s.needForceStop(ctx, host, instanceName, runnerName, l)
 
-	l.Info("will stop instance")
-	client := host.Client
+	l.Info("will stop instance", "force", force)
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to acquire lxd client: %+v", err)
+	}
+	defer release()
 	hostAddr := host.HostConfig.LxdHost
 	reqState := api.InstanceStatePut{
 		Action:  "stop",
 		Timeout: -1,
+		Force:   force,
 	}
-	timer := metric.NewLXDAPITimer(hostAddr, "UpdateInstanceState")
+	timer := metric.NewLXDAPITimer(ctx, hostAddr, "UpdateInstanceState")
 	op, err := client.UpdateInstanceState(instanceName, reqState, "")
 	timer.ObserveDuration(err)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to stop instance: %+v", err)
 	}
-	if err := op.Wait(); err != nil && !strings.EqualFold(err.Error(), "The instance is already stopped") {
+	if err := lxdclient.WaitOperation(ctx, op); err != nil && !strings.EqualFold(err.Error(), "The instance is already stopped") {
 		return nil, status.Errorf(codes.Internal, "failed to wait stopping instance: %+v", err)
 	}
 
 	l.Info("will delete instance")
-	timer = metric.NewLXDAPITimer(hostAddr, "DeleteInstance")
+	timer = metric.NewLXDAPITimer(ctx, hostAddr, "DeleteInstance")
 	op, err = client.DeleteInstance(instanceName)
 	timer.ObserveDuration(err)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete instance: %+v", err)
 	}
-	if err := op.Wait(); err != nil {
+	if err := lxdclient.WaitOperation(ctx, op); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to wait deleting instance: %+v", err)
 	}
 
 	l.Info("Success DeleteInstance")
+	webhook.Emit(webhook.Event{
+		Type:         webhook.EventInstanceDeleted,
+		RunnerName:   runnerName,
+		Host:         hostAddr,
+		InstanceName: instanceName,
+	})
 
 	return &pb.DeleteInstanceResponse{}, nil
 }
+
+// SetDeleteTombstoneTTL set period of remembering deleted instances. 0 means disabled.
+func (s *ShoesLXDMultiServer) SetDeleteTombstoneTTL(ttl time.Duration) {
+	s.tombstoneTTL = ttl
+}
+
+// tombstoneKeyCloudID return key of tombstone for cloud_id.
+// Host-qualified cloud_id is used, because instances in different hosts can have same name.
+func tombstoneKeyCloudID(cloudID string) string {
+	return "cloud_id/" + cloudID
+}
+
+func tombstoneKeyRunnerName(runnerName string) string {
+	return "runner_name/" + runnerName
+}
+
+// saveTombstone record that the instance is deleted. Failure is only logged, the deletion is not idempotent without it.
+func (s *ShoesLXDMultiServer) saveTombstone(ctx context.Context, key, value string, l *slog.Logger) {
+	if s.tombstoneTTL == 0 {
+		return
+	}
+	if err := s.store.SaveTombstone(context.WithoutCancel(ctx), key, value, s.tombstoneTTL); err != nil {
+		l.Warn("failed to save tombstone", "key", key, "err", err.Error())
+	}
+}
+
+// loadTombstone return value of tombstone, and true if the instance is deleted recently
+func (s *ShoesLXDMultiServer) loadTombstone(ctx context.Context, key string, l *slog.Logger) (string, bool) {
+	if s.tombstoneTTL == 0 {
+		return "", false
+	}
+	v, err := s.store.LoadTombstone(ctx, key)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			l.Warn("failed to load tombstone", "key", key, "err", err.Error())
+		}
+		return "", false
+	}
+	return v, true
+}
+
+// isRecentlyDeleted return true if the tombstone of key exists
+func (s *ShoesLXDMultiServer) isRecentlyDeleted(ctx context.Context, key string, l *slog.Logger) bool {
+	_, ok := s.loadTombstone(ctx, key, l)
+	return ok
+}
+
+// DeleteByRunnerName delete instance that is allocated to the runner
+func (s *ShoesLXDMultiServer) DeleteByRunnerName(ctx context.Context, req *pb.DeleteByRunnerNameRequest) (*pb.DeleteByRunnerNameResponse, error) {
+	slog.Info("DeleteByRunnerName", "req", req)
+	l := slog.With("method", "DeleteByRunnerName", "runnerName", req.RunnerName)
+	if _, err := runner.ToUUID(req.RunnerName); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse runner name: %+v", err)
+	}
+
+	targetLXDHosts, err := s.validateTargetHosts(ctx, req.TargetHosts, l)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to validate target hosts: %+v", err)
+	}
+
+	host, instanceName, err := findInstanceByRunnerName(ctx, targetLXDHosts, req.RunnerName)
+	if err != nil {
+		if errors.Is(err, ErrInstanceIsNotFound) {
+			if cloudID, ok := s.loadTombstone(ctx, tombstoneKeyRunnerName(req.RunnerName), l); ok {
+				l.Info("instance is already deleted", "cloudID", cloudID)
+				return &pb.DeleteByRunnerNameResponse{CloudId: cloudID}, nil
+			}
+			return nil, status.Errorf(codes.NotFound, "instance of runner %s is not found", req.RunnerName)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to find instance of runner: %+v", err)
+	}
+
+	l = l.With("instanceName", instanceName)
+	cloudID := formatCloudID(host.HostConfig.LxdHost, instanceName)
+	if _, err := s.deleteInstanceWithJournal(ctx, &pb.DeleteInstanceRequest{CloudId: cloudID}, req.RunnerName, []*lxdclient.LXDHost{host}, l); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteByRunnerNameResponse{CloudId: cloudID}, nil
+}
+
+// findInstanceByRunnerName return the instance that is allocated to runnerName.
+// It queries hosts instead of cache, because the instance may be allocated just before.
+// Quarantined instance is not returned, it is deleted by quarantine.
+func findInstanceByRunnerName(ctx context.Context, targetLXDHosts []*lxdclient.LXDHost, runnerName string) (*lxdclient.LXDHost, string, error) {
+	type found struct {
+		host         *lxdclient.LXDHost
+		instanceName string
+	}
+	var mu sync.Mutex
+	var result *found
+
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, host := range targetLXDHosts {
+		eg.Go(func() error {
+			client, release, err := host.Acquire(ctx)
+			if err != nil {
+				return fmt.Errorf("acquire lxd client of %s: %w", host.HostConfig.LxdHost, err)
+			}
+			defer release()
+
+			instances, err := lxdclient.GetAnyInstances(ctx, client, host.HostConfig.LxdHost)
+			if err != nil {
+				return fmt.Errorf("get instances of %s: %w", host.HostConfig.LxdHost, err)
+			}
+			for _, i := range instances {
+				if i.Config[lxdclient.ConfigKeyRunnerName] != runnerName || isQuarantined(i) {
+					continue
+				}
+				mu.Lock()
+				result = &found{host: host, instanceName: i.Name}
+				mu.Unlock()
+				return nil
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, "", err
+	}
+	if result == nil {
+		return nil, "", ErrInstanceIsNotFound
+	}
+	return result.host, result.instanceName, nil
+}
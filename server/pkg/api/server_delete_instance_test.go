@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+)
+
+func TestTombstoneKeyCloudID(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(config.NewHostConfigMap(), nil, nil, 100, store.NewMemory(), nil)
+	if err != nil {
+		t.Fatalf("failed to create server: %+v", err)
+	}
+	s.SetDeleteTombstoneTTL(time.Minute)
+
+	s.saveTombstone(ctx, tombstoneKeyCloudID(formatCloudID("host-a", "instance-1")), "runner-1", slog.Default())
+
+	tests := []struct {
+		name    string
+		cloudID string
+		want    bool
+	}{
+		{name: "deleted instance", cloudID: formatCloudID("host-a", "instance-1"), want: true},
+		{name: "same name in other host", cloudID: formatCloudID("host-b", "instance-1"), want: false},
+		{name: "older format", cloudID: "instance-1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.isRecentlyDeleted(ctx, tombstoneKeyCloudID(tt.cloudID), slog.Default()); got != tt.want {
+				t.Errorf("isRecentlyDeleted(%q) = %v, want %v", tt.cloudID, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -24,23 +25,31 @@ var (
 // result with a single API call, instead of querying every target host. When the cache
 // does not know the instance (cache miss or a stale entry), it falls back to querying all
 // target hosts.
-func (s *ShoesLXDMultiServer) isExistInstance(targetLXDHosts []*lxdclient.LXDHost, instanceName string, logger *slog.Logger) (*lxdclient.LXDHost, error) {
+// The cache is used only if all target hosts have fresh cache, because the instance in a host
+// without cache can not be checked, and the instance in other host may be deleted by mistake.
+func (s *ShoesLXDMultiServer) isExistInstance(ctx context.Context, targetLXDHosts []*lxdclient.LXDHost, instanceName string, logger *slog.Logger) (*lxdclient.LXDHost, error) {
+	if !hasFreshStatusCache(targetLXDHosts) {
+		return isExistInstanceInHosts(ctx, targetLXDHosts, instanceName, logger)
+	}
 	if host := findInstanceHostFromCache(targetLXDHosts, instanceName); host != nil {
 		// The cache can be stale, so confirm the instance really exists on the host with
 		// a single API call before trusting it.
-		if err := isExistInstanceWithTimeout(host, instanceName); err == nil {
+		if err := isExistInstanceWithTimeout(ctx, host, instanceName); err == nil {
 			return host, nil
 		}
 		logger.With("host", host.HostConfig.LxdHost).Debug("instance found in cache but not confirmed on host, fall back to querying all hosts", "instanceName", instanceName)
 	}
 
-	return isExistInstanceInHosts(targetLXDHosts, instanceName, logger)
+	return isExistInstanceInHosts(ctx, targetLXDHosts, instanceName, logger)
 }
 
 // findInstanceHostFromCache returns the host that has the instance according to the
 // in-memory resource cache. It returns nil when no cached host has the instance, in which
 // case the caller should fall back to querying the hosts directly.
+// It also returns nil when multiple cached hosts have the instance, so the ambiguity is
+// checked against the hosts.
 func findInstanceHostFromCache(targetLXDHosts []*lxdclient.LXDHost, instanceName string) *lxdclient.LXDHost {
+	var found *lxdclient.LXDHost
 	for _, host := range targetLXDHosts {
 		status, err := lxdclient.GetStatusCache(host.HostConfig.LxdHost)
 		if err != nil {
@@ -49,24 +58,40 @@ func findInstanceHostFromCache(targetLXDHosts []*lxdclient.LXDHost, instanceName
 		}
 		for _, instance := range status.Resource.Instances {
 			if instance.Name == instanceName {
-				return host
+				if found != nil {
+					return nil
+				}
+				found = host
+				break
 			}
 		}
 	}
-	return nil
+	return found
+}
+
+// hasFreshStatusCache return true if all target hosts have cache that is refreshed successfully and not stale
+func hasFreshStatusCache(targetLXDHosts []*lxdclient.LXDHost) bool {
+	for _, host := range targetLXDHosts {
+		status, err := lxdclient.GetStatusCache(host.HostConfig.LxdHost)
+		if err != nil || !status.IsGood || status.IsStale() {
+			return false
+		}
+	}
+	return true
 }
 
 // isExistInstanceInHosts searches the instance by querying all target hosts.
-func isExistInstanceInHosts(targetLXDHosts []*lxdclient.LXDHost, instanceName string, logger *slog.Logger) (*lxdclient.LXDHost, error) {
+// It returns ErrInstanceIsAmbiguous if multiple hosts have the instance.
+func isExistInstanceInHosts(ctx context.Context, targetLXDHosts []*lxdclient.LXDHost, instanceName string, logger *slog.Logger) (*lxdclient.LXDHost, error) {
 	eg := errgroup.Group{}
-	var foundHost *lxdclient.LXDHost
-	foundHost = nil
+	var mu sync.Mutex
+	var foundHosts []*lxdclient.LXDHost
 
 	for _, host := range targetLXDHosts {
 		func(host *lxdclient.LXDHost) {
 			eg.Go(func() error {
 				l := logger.With("host", host.HostConfig.LxdHost)
-				err := isExistInstanceWithTimeout(host, instanceName)
+				err := isExistInstanceWithTimeout(ctx, host, instanceName)
 				if err != nil {
 					switch {
 					case errors.Is(err, ErrInstanceIsNotFound):
@@ -81,7 +106,9 @@ func isExistInstanceInHosts(targetLXDHosts []*lxdclient.LXDHost, instanceName st
 					}
 				}
 
-				foundHost = host
+				mu.Lock()
+				foundHosts = append(foundHosts, host)
+				mu.Unlock()
 				return nil
 			})
 		}(host)
@@ -90,11 +117,17 @@ func isExistInstanceInHosts(targetLXDHosts []*lxdclient.LXDHost, instanceName st
 	if err := eg.Wait(); err != nil {
 		return nil, fmt.Errorf("failed to get instance: %w", err)
 	}
-	if foundHost == nil {
+	switch len(foundHosts) {
+	case 0:
 		return nil, ErrInstanceIsNotFound
+	case 1:
+		return foundHosts[0], nil
 	}
-
-	return foundHost, nil
+	var hosts []string
+	for _, host := range foundHosts {
+		hosts = append(hosts, host.HostConfig.LxdHost)
+	}
+	return nil, fmt.Errorf("%w: %s exists in %s", ErrInstanceIsAmbiguous, instanceName, strings.Join(hosts, ", "))
 }
 
 var (
@@ -102,18 +135,21 @@ var (
 	ErrTimeoutGetInstance = fmt.Errorf("timeout of GetInstance")
 )
 
-func isExistInstanceWithTimeout(targetLXDHost *lxdclient.LXDHost, instanceName string) error {
-	cctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+func isExistInstanceWithTimeout(ctx context.Context, targetLXDHost *lxdclient.LXDHost, instanceName string) error {
+	cctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
-	targetLXDHost.APICallMutex.Lock()
-	defer targetLXDHost.APICallMutex.Unlock()
-
-	c := targetLXDHost.Client.WithContext(cctx)
-	defer targetLXDHost.Client.WithContext(context.Background())
+	c, release, err := targetLXDHost.Acquire(cctx)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return ErrTimeoutGetInstance
+		}
+		return fmt.Errorf("failed to acquire lxd client: %w", err)
+	}
+	defer release()
 
-	timer := metric.NewLXDAPITimer(targetLXDHost.HostConfig.LxdHost, "GetInstance")
-	_, _, err := c.GetInstance(instanceName)
+	timer := metric.NewLXDAPITimer(cctx, targetLXDHost.HostConfig.LxdHost, "GetInstance")
+	_, _, err = c.GetInstance(instanceName)
 	timer.ObserveDuration(err)
 	if err != nil {
 		switch {
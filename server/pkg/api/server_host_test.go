@@ -2,6 +2,7 @@ package api
 
 import (
 	"testing"
+	"time"
 
 	"github.com/lxc/lxd/shared/api"
 
@@ -64,3 +65,43 @@ func TestFindInstanceHostFromCacheSkipsHostWithoutCache(t *testing.T) {
 		t.Errorf("findInstanceHostFromCache() = %v, want %v", got, hostHit)
 	}
 }
+
+// TestHasFreshStatusCache ensures the cache is not used to find the instance of older cloud_id
+// if a host does not have cache, because the instance in the host can not be checked.
+func TestHasFreshStatusCache(t *testing.T) {
+	hostGood := &lxdclient.LXDHost{HostConfig: config.HostConfig{LxdHost: "test-fresh-good"}}
+	hostBad := &lxdclient.LXDHost{HostConfig: config.HostConfig{LxdHost: "test-fresh-bad"}}
+	hostMiss := &lxdclient.LXDHost{HostConfig: config.HostConfig{LxdHost: "test-fresh-miss"}}
+
+	if err := lxdclient.SetStatusCache(hostGood.HostConfig.LxdHost, lxdclient.LXDStatus{
+		IsGood:        true,
+		LastUpdatedAt: time.Now(),
+		Resource:      lxdclient.Resource{Instances: []api.Instance{{Name: "wanted"}}},
+	}); err != nil {
+		t.Fatalf("failed to set status cache: %+v", err)
+	}
+	if err := lxdclient.SetStatusCache(hostBad.HostConfig.LxdHost, lxdclient.LXDStatus{
+		IsGood:        false,
+		LastUpdatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("failed to set status cache: %+v", err)
+	}
+
+	tests := []struct {
+		name    string
+		targets []*lxdclient.LXDHost
+		want    bool
+	}{
+		{name: "all hosts have fresh cache", targets: []*lxdclient.LXDHost{hostGood}, want: true},
+		{name: "a host does not have cache", targets: []*lxdclient.LXDHost{hostGood, hostMiss}, want: false},
+		{name: "latest refresh of a host is failed", targets: []*lxdclient.LXDHost{hostGood, hostBad}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasFreshStatusCache(tt.targets); got != tt.want {
+				t.Errorf("hasFreshStatusCache() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/journal"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+)
+
+// ListJournal return journal of allocation and deletion
+func (s *ShoesLXDMultiServer) ListJournal(ctx context.Context, req *pb.ListJournalRequest) (*pb.ListJournalResponse, error) {
+	if s.journal == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "journal is disabled")
+	}
+
+	q := journal.Query{
+		RunnerName: req.RunnerName,
+		Limit:      int(req.Limit),
+	}
+	if req.Since != nil {
+		q.Since = req.Since.AsTime()
+	}
+	if req.Until != nil {
+		q.Until = req.Until.AsTime()
+	}
+
+	entries, err := s.journal.List(q)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list journal: %+v", err)
+	}
+
+	resp := &pb.ListJournalResponse{}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, toPbJournalEntry(e))
+	}
+	return resp, nil
+}
+
+func toPbJournalEntry(e journal.Entry) *pb.JournalEntry {
+	toTimestamp := func(t time.Time) *timestamppb.Timestamp {
+		if t.IsZero() {
+			return nil
+		}
+		return timestamppb.New(t)
+	}
+
+	return &pb.JournalEntry{
+		Id:           e.ID,
+		Operation:    string(e.Operation),
+		RunnerName:   e.RunnerName,
+		Host:         e.Host,
+		InstanceName: e.InstanceName,
+		ImageAlias:   e.ImageAlias,
+		ResourceType: e.ResourceType,
+		StartedAt:    toTimestamp(e.StartedAt),
+		AllocatedAt:  toTimestamp(e.AllocatedAt),
+		FinishedAt:   toTimestamp(e.FinishedAt),
+		Outcome:      string(e.Outcome),
+		Message:      e.Message,
+	}
+}
+
+// beginJournal record new entry, and return its ID.
+// Failure of journal does not fail the request, so 0 is returned if failed.
+func (s *ShoesLXDMultiServer) beginJournal(e journal.Entry, l *slog.Logger) uint64 {
+	id, err := s.journal.Begin(e)
+	if err != nil {
+		l.Warn("failed to begin journal entry", "err", err.Error())
+		return 0
+	}
+	return id
+}
+
+func (s *ShoesLXDMultiServer) updateJournal(id uint64, fn func(e *journal.Entry), l *slog.Logger) {
+	if id == 0 {
+		return
+	}
+	if err := s.journal.Update(id, fn); err != nil {
+		l.Warn("failed to update journal entry", "id", id, "err", err.Error())
+	}
+}
+
+func (s *ShoesLXDMultiServer) finishJournal(id uint64, err error, l *slog.Logger) {
+	if id == 0 {
+		return
+	}
+	if err := s.journal.Finish(id, err); err != nil {
+		l.Warn("failed to finish journal entry", "id", id, "err", err.Error())
+	}
+}
+
+// runnerNameFromJournal return runner name that the instance is allocated to, empty if not recorded
+func (s *ShoesLXDMultiServer) runnerNameFromJournal(instanceName string, l *slog.Logger) string {
+	entries, err := s.journal.List(journal.Query{InstanceName: instanceName, Limit: 1})
+	if err != nil {
+		l.Warn("failed to list journal", "err", err.Error())
+		return ""
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	return entries[0].RunnerName
+}
+
+// ReconcileJournal check real state of instances in entries that are not finished by previous process,
+// and mark them as interrupted. Pending entries must be listed before server starts serving.
+func (s *ShoesLXDMultiServer) ReconcileJournal(ctx context.Context, entries []journal.Entry) {
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			// entries that are not reconciled are left pending, and reconciled by next process
+			return
+		}
+		l := slog.With("method", "ReconcileJournal", "id", e.ID, "operation", e.Operation, "runnerName", e.RunnerName, "host", e.Host, "instanceName", e.InstanceName)
+
+		outcome, message := s.reconcileJournalEntry(ctx, e)
+		if err := s.journal.Update(e.ID, func(entry *journal.Entry) {
+			entry.FinishedAt = time.Now()
+			entry.Outcome = outcome
+			entry.Message = message
+		}); err != nil {
+			l.Warn("failed to update journal entry", "err", err.Error())
+			continue
+		}
+		l.Warn("reconciled unfinished journal entry", "outcome", outcome, "message", message)
+	}
+}
+
+func (s *ShoesLXDMultiServer) reconcileJournalEntry(ctx context.Context, e journal.Entry) (journal.Outcome, string) {
+	if e.Host == "" || e.InstanceName == "" {
+		return journal.OutcomeInterrupted, "server stopped before instance is determined"
+	}
+
+	instance, err := s.getInstanceForReconcile(ctx, e.Host, e.InstanceName)
+	if err != nil {
+		if errors.Is(err, ErrInstanceIsNotFound) {
+			if e.Operation == journal.OperationDelete {
+				return journal.OutcomeSucceeded, "server stopped while deleting instance, but instance is deleted"
+			}
+			return journal.OutcomeInterrupted, "server stopped while setting up instance, instance is not found"
+		}
+		return journal.OutcomeInterrupted, fmt.Sprintf("server stopped while processing, failed to get instance state: %v", err)
+	}
+
+	if e.Operation == journal.OperationDelete {
+		return journal.OutcomeInterrupted, fmt.Sprintf("server stopped while deleting instance, instance status is %s", instance.Status)
+	}
+	return journal.OutcomeInterrupted, fmt.Sprintf("server stopped while setting up instance, instance status is %s and allocated runner is %q", instance.Status, instance.Config[lxdclient.ConfigKeyRunnerName])
+}
+
+func (s *ShoesLXDMultiServer) getInstanceForReconcile(ctx context.Context, host, instanceName string) (*api.Instance, error) {
+	hc, err := s.hostConfigs.Load(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load host config: %w", err)
+	}
+	hosts, _, err := lxdclient.ConnectLXDs(ctx, []config.HostConfig{*hc})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect LXD: %w", err)
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("failed to connect LXD")
+	}
+
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	client, release, err := hosts[0].Acquire(cctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lxd client: %w", err)
+	}
+	defer release()
+
+	timer := metric.NewLXDAPITimer(ctx, host, "GetInstance")
+	instance, _, err := client.GetInstance(instanceName)
+	timer.ObserveDuration(err)
+	if err != nil {
+		if strings.Contains(err.Error(), "Instance not found") {
+			return nil, ErrInstanceIsNotFound
+		}
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+	return instance, nil
+}
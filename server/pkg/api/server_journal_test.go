@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/journal"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+)
+
+func newTestJournalServer(t *testing.T) *ShoesLXDMultiServer {
+	t.Helper()
+
+	j, err := journal.Open(filepath.Join(t.TempDir(), "journal.db"))
+	if err != nil {
+		t.Fatalf("failed to open journal: %+v", err)
+	}
+	t.Cleanup(func() { j.Close() })
+
+	s, err := New(config.NewHostConfigMap(), nil, nil, 100, store.NewMemory(), j)
+	if err != nil {
+		t.Fatalf("failed to create server: %+v", err)
+	}
+	return s
+}
+
+func TestListJournal(t *testing.T) {
+	ctx := context.Background()
+	s := newTestJournalServer(t)
+
+	allocate := s.beginJournal(journal.Entry{Operation: journal.OperationAllocate, RunnerName: "runner-1"}, slog.Default())
+	s.updateJournal(allocate, func(e *journal.Entry) { e.InstanceName = "instance-1" }, slog.Default())
+	s.finishJournal(allocate, nil, slog.Default())
+
+	del := s.beginJournal(journal.Entry{
+		Operation:    journal.OperationDelete,
+		RunnerName:   s.runnerNameFromJournal("instance-1", slog.Default()),
+		InstanceName: "instance-1",
+	}, slog.Default())
+	s.finishJournal(del, errors.New("failed to stop instance"), slog.Default())
+
+	resp, err := s.ListJournal(ctx, &pb.ListJournalRequest{RunnerName: "runner-1"})
+	if err != nil {
+		t.Fatalf("failed to list journal: %+v", err)
+	}
+	if len(resp.Entries) != 2 {
+		t.Fatalf("ListJournal() returned %d entries, want 2", len(resp.Entries))
+	}
+	if got := resp.Entries[0]; got.Operation != "allocate" || got.Outcome != "succeeded" || got.FinishedAt == nil || got.AllocatedAt != nil {
+		t.Errorf("allocate entry = %+v", got)
+	}
+	if got := resp.Entries[1]; got.Operation != "delete" || got.Outcome != "failed" || got.Message != "failed to stop instance" {
+		t.Errorf("delete entry = %+v", got)
+	}
+}
+
+func TestListJournal_Disabled(t *testing.T) {
+	s, err := New(config.NewHostConfigMap(), nil, nil, 100, store.NewMemory(), nil)
+	if err != nil {
+		t.Fatalf("failed to create server: %+v", err)
+	}
+	if _, err := s.ListJournal(context.Background(), &pb.ListJournalRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ListJournal() with disabled journal returned %v, want FailedPrecondition", err)
+	}
+	// requests must not fail without journal
+	id := s.beginJournal(journal.Entry{}, slog.Default())
+	s.finishJournal(id, nil, slog.Default())
+}
+
+func TestReconcileJournal(t *testing.T) {
+	s := newTestJournalServer(t)
+
+	s.beginJournal(journal.Entry{Operation: journal.OperationAllocate, RunnerName: "runner-1"}, slog.Default())
+	id := s.beginJournal(journal.Entry{Operation: journal.OperationAllocate, RunnerName: "runner-2"}, slog.Default())
+	// unknown host can't be checked
+	s.updateJournal(id, func(e *journal.Entry) {
+		e.Host = "unknown-host"
+		e.InstanceName = "instance-2"
+	}, slog.Default())
+
+	pending, err := s.journal.ListPending()
+	if err != nil {
+		t.Fatalf("failed to list pending entries: %+v", err)
+	}
+	s.ReconcileJournal(context.Background(), pending)
+
+	if pending, _ := s.journal.ListPending(); len(pending) != 0 {
+		t.Errorf("pending entries are remaining after reconcile: %+v", pending)
+	}
+	entries, _ := s.journal.List(journal.Query{})
+	for _, e := range entries {
+		if e.Outcome != journal.OutcomeInterrupted || e.Message == "" || e.FinishedAt.IsZero() {
+			t.Errorf("reconciled entry = %+v, want interrupted with message", e)
+		}
+	}
+}
+
+func TestReconcileJournal_Canceled(t *testing.T) {
+	s := newTestJournalServer(t)
+
+	s.beginJournal(journal.Entry{Operation: journal.OperationAllocate, RunnerName: "runner-1"}, slog.Default())
+	pending, err := s.journal.ListPending()
+	if err != nil {
+		t.Fatalf("failed to list pending entries: %+v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.ReconcileJournal(ctx, pending)
+
+	if pending, _ := s.journal.ListPending(); len(pending) != 1 {
+		t.Errorf("ListPending() returned %d entries after canceled reconcile, want 1", len(pending))
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+
+	lxd "github.com/lxc/lxd/client"
+	"github.com/lxc/lxd/shared/api"
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+)
+
+// GetSetupLog return log of setup script in instance
+func (s *ShoesLXDMultiServer) GetSetupLog(req *pb.GetSetupLogRequest, stream pb.ShoesLXDMulti_GetSetupLogServer) error {
+	ctx := stream.Context()
+	slog.Info("GetSetupLog", "req", req)
+	l := slog.With("method", "GetSetupLog", "instanceName", req.CloudId)
+
+	if req.TailLines < 0 {
+		return status.Errorf(codes.InvalidArgument, "tail_lines must not be negative")
+	}
+	if req.Follow && req.Source != pb.SetupLogSource_SETUP_LOG_SOURCE_JOURNAL {
+		return status.Errorf(codes.InvalidArgument, "follow is only supported in journal")
+	}
+
+	host, instanceName, err := s.locateInstance(ctx, req.CloudId, req.TargetHosts, l)
+	if err != nil {
+		return err
+	}
+
+	sender := &streamSender{}
+	defer sender.Close()
+	w := sender.writer(func(data []byte) error {
+		return stream.Send(&pb.GetSetupLogResponse{Data: data})
+	})
+
+	switch req.Source {
+	case pb.SetupLogSource_SETUP_LOG_SOURCE_CONSOLE:
+		err = copyConsoleLog(ctx, host, instanceName, w)
+	default:
+		err = execSetupLog(ctx, host, instanceName, setupLogCommand(int(req.TailLines), req.Follow), w, l)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return status.Errorf(codes.Internal, "failed to get setup log: %+v", err)
+	}
+	return nil
+}
+
+// setupLogCommand return command that print journal of setup script
+func setupLogCommand(tailLines int, follow bool) []string {
+	cmd := []string{"journalctl", "--unit", setupUnitName, "--output", "cat", "--no-pager"}
+	if tailLines > 0 {
+		cmd = append(cmd, "--lines", strconv.Itoa(tailLines))
+	}
+	if follow {
+		cmd = append(cmd, "--follow")
+	}
+	return cmd
+}
+
+// copyConsoleLog write console output of instance to w
+func copyConsoleLog(ctx context.Context, host *lxdclient.LXDHost, instanceName string, w io.Writer) error {
+	client, release, err := host.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire lxd client: %w", err)
+	}
+	defer release()
+
+	timer := metric.NewLXDAPITimer(ctx, host.HostConfig.LxdHost, "GetInstanceConsoleLog")
+	r, err := client.GetInstanceConsoleLog(instanceName, &lxd.InstanceConsoleLogArgs{})
+	timer.ObserveDuration(err)
+	if err != nil {
+		return fmt.Errorf("get console log: %w", err)
+	}
+	defer r.Close()
+
+	if _, err := io.Copy(w, r); err != nil {
+		return fmt.Errorf("copy console log: %w", err)
+	}
+	return nil
+}
+
+// execSetupLog execute cmd in instance and write its stdout to w
+func execSetupLog(ctx context.Context, host *lxdclient.LXDHost, instanceName string, cmd []string, w io.WriteCloser, l *slog.Logger) error {
+	stderr := &bufferCloser{Buffer: &bytes.Buffer{}}
+	code, err := execStream(ctx, host, instanceName, api.InstanceExecPost{Command: cmd}, w, stderr, l)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("journalctl is exited with %d: %s", code, stderr.String())
+	}
+	return nil
+}
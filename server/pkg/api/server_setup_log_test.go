@@ -0,0 +1,28 @@
+package api
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSetupLogCommand(t *testing.T) {
+	tests := []struct {
+		tailLines int
+		follow    bool
+		want      []string
+	}{
+		{
+			want: []string{"journalctl", "--unit", "myshoes-setup", "--output", "cat", "--no-pager"},
+		},
+		{
+			tailLines: 100,
+			follow:    true,
+			want:      []string{"journalctl", "--unit", "myshoes-setup", "--output", "cat", "--no-pager", "--lines", "100", "--follow"},
+		},
+	}
+	for _, tt := range tests {
+		if got := setupLogCommand(tt.tailLines, tt.follow); !slices.Equal(got, tt.want) {
+			t.Errorf("setupLogCommand(%d, %t) = %v, want %v", tt.tailLines, tt.follow, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// setupScriptPath is path of setup script in instance. /run is tmpfs, so the runner token is not written to disk.
+	setupScriptPath = "/run/myshoes_setup_script"
+	// setupEnvPath is path of secret environment variables for setup script in instance
+	setupEnvPath = "/run/myshoes_setup_env"
+)
+
+// setupScriptWrapper is executed by myshoes-setup unit with arguments of setup script path, env path and interpreter.
+// It opens setup script and loads secret environment variables, and removes them before starting setup script.
+// The interpreter reads setup script from the opened descriptor, because /run is mounted with noexec.
+const setupScriptWrapper = `script=$0 envfile=$1; shift
+exec 3<"$script"
+if [ -f "$envfile" ]; then set -a; . "$envfile"; set +a; fi
+rm -f "$script" "$envfile"
+exec "$@" /dev/fd/3`
+
+// envNamePattern is pattern of name of environment variable that can be set by shell
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateSecretEnvironment return error if name of environment variable is invalid
+func validateSecretEnvironment(env map[string]string) error {
+	for k := range env {
+		if !envNamePattern.MatchString(k) {
+			return fmt.Errorf("invalid name of environment variable: %q", k)
+		}
+	}
+	return nil
+}
+
+// setupEnvFile return content of env file that is sourced by setupScriptWrapper
+func setupEnvFile(env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s='%s'\n", k, strings.ReplaceAll(env[k], `'`, `'\''`))
+	}
+	return b.String()
+}
+
+// setupScriptInterpreter return command that executes setup script from shebang, or /bin/sh if no shebang
+func setupScriptInterpreter(script string) []string {
+	line, _, _ := strings.Cut(script, "\n")
+	if interpreter, ok := strings.CutPrefix(line, "#!"); ok {
+		if fields := strings.Fields(interpreter); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"/bin/sh"}
+}
+
+// setupScriptCommand return command of myshoes-setup unit
+func setupScriptCommand(script string) []string {
+	return append([]string{"/bin/sh", "-c", setupScriptWrapper, setupScriptPath, setupEnvPath}, setupScriptInterpreter(script)...)
+}
+
+// redactRequest return request of gRPC for logging, that secrets are removed from
+func redactRequest(req any) any {
+	switch r := req.(type) {
+	case *pb.AddInstanceRequest:
+		return redactAddInstanceRequest(r)
+	case *pb.AddInstancesRequest:
+		c := proto.Clone(r).(*pb.AddInstancesRequest)
+		for i, item := range c.Instances {
+			c.Instances[i] = redactAddInstanceRequest(item)
+		}
+		return c
+	default:
+		return req
+	}
+}
+
+// redactAddInstanceRequest return copy of req for logging, that secrets are removed from
+func redactAddInstanceRequest(req *pb.AddInstanceRequest) *pb.AddInstanceRequest {
+	r := proto.Clone(req).(*pb.AddInstanceRequest)
+	if r.SetupScript != "" {
+		r.SetupScript = "<redacted>"
+	}
+	for k := range r.SecretEnvironment {
+		r.SecretEnvironment[k] = "<redacted>"
+	}
+	return r
+}
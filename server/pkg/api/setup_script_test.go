@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	pb "github.com/whywaita/shoes-lxd-multi/proto.go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+)
+
+func TestSetupScriptWrapper(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("/bin/sh is not found")
+	}
+
+	dir := t.TempDir()
+	scriptPath := filepath.Join(dir, "setup_script")
+	envPath := filepath.Join(dir, "setup_env")
+	script := "#!/bin/sh\necho \"$RUNNER_TOKEN\"\n"
+	if err := os.WriteFile(scriptPath, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"RUNNER_TOKEN": `it's "secret" $HOME`}
+	if err := os.WriteFile(envPath, []byte(setupEnvFile(env)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	args := append([]string{"-c", setupScriptWrapper, scriptPath, envPath}, setupScriptInterpreter(script)...)
+	out, err := exec.Command("/bin/sh", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to execute wrapper: %v: %s", err, out)
+	}
+	if got := strings.TrimSpace(string(out)); got != env["RUNNER_TOKEN"] {
+		t.Errorf("output = %q, want %q", got, env["RUNNER_TOKEN"])
+	}
+	for _, path := range []string{scriptPath, envPath} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s is not removed", path)
+		}
+	}
+}
+
+func TestSetupScriptInterpreter(t *testing.T) {
+	tests := []struct {
+		script string
+		want   []string
+	}{
+		{script: "#!/bin/bash\necho", want: []string{"/bin/bash"}},
+		{script: "#!/usr/bin/env bash\necho", want: []string{"/usr/bin/env", "bash"}},
+		{script: "echo", want: []string{"/bin/sh"}},
+		{script: "#!\necho", want: []string{"/bin/sh"}},
+	}
+	for _, tt := range tests {
+		if got := setupScriptInterpreter(tt.script); !slices.Equal(got, tt.want) {
+			t.Errorf("setupScriptInterpreter(%q) = %v, want %v", tt.script, got, tt.want)
+		}
+	}
+}
+
+func TestValidateSecretEnvironment(t *testing.T) {
+	if err := validateSecretEnvironment(map[string]string{"RUNNER_TOKEN": "x", "_A1": "y"}); err != nil {
+		t.Errorf("validateSecretEnvironment() error = %v, want nil", err)
+	}
+	for _, name := range []string{"1A", "A-B", "A=B", "A;rm"} {
+		if err := validateSecretEnvironment(map[string]string{name: "x"}); err == nil {
+			t.Errorf("validateSecretEnvironment(%q) is succeeded, want error", name)
+		}
+	}
+}
+
+func TestRedactAddInstanceRequest(t *testing.T) {
+	req := &pb.AddInstanceRequest{
+		RunnerName:        "myshoes-00000000-0000-0000-0000-000000000000",
+		SetupScript:       "echo AAAA0000",
+		SecretEnvironment: map[string]string{"RUNNER_TOKEN": "BBBB1111"},
+	}
+	r := redactAddInstanceRequest(req)
+	if strings.Contains(r.String(), "AAAA0000") || strings.Contains(r.String(), "BBBB1111") {
+		t.Errorf("redacted request contains secret: %s", r.String())
+	}
+	if req.SetupScript != "echo AAAA0000" || req.SecretEnvironment["RUNNER_TOKEN"] != "BBBB1111" {
+		t.Errorf("redactAddInstanceRequest() modified the request")
+	}
+}
+
+func TestLoggingUnaryServerInterceptor_Redact(t *testing.T) {
+	var buf bytes.Buffer
+	orig := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(orig) })
+
+	item := &pb.AddInstanceRequest{
+		RunnerName:        "myshoes-00000000-0000-0000-0000-000000000000",
+		SetupScript:       "echo AAAA0000",
+		SecretEnvironment: map[string]string{"RUNNER_TOKEN": "BBBB1111"},
+	}
+	tests := []struct {
+		name   string
+		method string
+		req    any
+		err    error
+	}{
+		{name: "AddInstance", method: pb.ShoesLXDMulti_AddInstance_FullMethodName, req: item},
+		{name: "AddInstance is failed", method: pb.ShoesLXDMulti_AddInstance_FullMethodName, req: item, err: status.Error(codes.Internal, "failed")},
+		{name: "AddInstances", method: pb.ShoesLXDMulti_AddInstances_FullMethodName, req: &pb.AddInstancesRequest{Instances: []*pb.AddInstanceRequest{item}}},
+	}
+
+	interceptor := metric.LoggingUnaryServerInterceptor(redactRequest)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			_, _ = interceptor(context.Background(), tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(context.Context, any) (any, error) {
+				return nil, tt.err
+			})
+			if !strings.Contains(buf.String(), item.RunnerName) {
+				t.Fatalf("request is not logged: %s", buf.String())
+			}
+			if strings.Contains(buf.String(), "AAAA0000") || strings.Contains(buf.String(), "BBBB1111") {
+				t.Errorf("log contains secret: %s", buf.String())
+			}
+		})
+	}
+	if item.SetupScript != "echo AAAA0000" || item.SecretEnvironment["RUNNER_TOKEN"] != "BBBB1111" {
+		t.Errorf("interceptor modified the request")
+	}
+}
@@ -0,0 +1,157 @@
+// Package auth provides token based authorization of gRPC methods.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Scope is permission that is granted to token
+type Scope string
+
+const (
+	// ScopeAdmin allows operations for debugging instances (e.g. exec command in instance)
+	ScopeAdmin Scope = "admin"
+)
+
+// Token is credential of API caller
+type Token struct {
+	// Name is name of caller, it is recorded in log
+	Name   string  `json:"name"`
+	Token  string  `json:"token"`
+	Scopes []Scope `json:"scopes"`
+}
+
+// ParseTokens parse JSON of tokens
+func ParseTokens(env string) ([]Token, error) {
+	if env == "" {
+		return nil, nil
+	}
+	var tokens []Token
+	if err := json.Unmarshal([]byte(env), &tokens); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	for i, t := range tokens {
+		if t.Name == "" || t.Token == "" {
+			return nil, fmt.Errorf("name and token are required in tokens[%d]", i)
+		}
+	}
+	return tokens, nil
+}
+
+type callerKey struct{}
+
+// CallerFromContext return name of caller that is authorized
+func CallerFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(callerKey{}).(string)
+	return name
+}
+
+// Authorizer check token of request for methods that require scope.
+// Methods that are not in method scopes are allowed without token.
+type Authorizer struct {
+	tokens       []Token
+	methodScopes map[string]Scope
+}
+
+// New create Authorizer. methodScopes is map of full method name and required scope.
+func New(tokens []Token, methodScopes map[string]Scope) *Authorizer {
+	return &Authorizer{
+		tokens:       tokens,
+		methodScopes: methodScopes,
+	}
+}
+
+// authorize return context with caller name if the request is allowed to call method
+func (a *Authorizer) authorize(ctx context.Context, method string) (context.Context, error) {
+	scope, ok := a.methodScopes[method]
+	if !ok {
+		return ctx, nil
+	}
+
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "%s requires token: %+v", method, err)
+	}
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) != 1 {
+			continue
+		}
+		if !slices.Contains(t.Scopes, scope) {
+			slog.WarnContext(ctx, "permission denied", "method", method, "caller", t.Name, "scope", scope)
+			return nil, status.Errorf(codes.PermissionDenied, "%s requires scope %q", method, scope)
+		}
+		return context.WithValue(ctx, callerKey{}, t.Name), nil
+	}
+	return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+}
+
+// bearerToken return token in authorization header
+func bearerToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", fmt.Errorf("metadata is not found")
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", fmt.Errorf("authorization header is not found")
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || token == "" {
+		return "", fmt.Errorf("authorization header must be Bearer token")
+	}
+	return token, nil
+}
+
+// UnaryServerInterceptor returns a new unary server interceptor that authorizes requests
+func (a *Authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a new stream server interceptor that authorizes requests
+func (a *Authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream is grpc.ServerStream that has context with caller name
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context return context of stream
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseTokens(t *testing.T) {
+	tokens, err := ParseTokens(`[{"name": "operator", "token": "secret", "scopes": ["admin"]}]`)
+	if err != nil {
+		t.Fatalf("failed to parse tokens: %+v", err)
+	}
+	if len(tokens) != 1 || tokens[0].Name != "operator" || tokens[0].Scopes[0] != ScopeAdmin {
+		t.Fatalf("ParseTokens() = %+v", tokens)
+	}
+
+	if _, err := ParseTokens(`[{"name": "operator"}]`); err == nil {
+		t.Fatalf("ParseTokens() must return error if token is empty")
+	}
+}
+
+func TestAuthorizer(t *testing.T) {
+	const method = "/test.Service/Admin"
+	a := New([]Token{
+		{Name: "operator", Token: "admin-token", Scopes: []Scope{ScopeAdmin}},
+		{Name: "viewer", Token: "viewer-token"},
+	}, map[string]Scope{method: ScopeAdmin})
+
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		method     string
+		wantCode   codes.Code
+		wantCaller string
+	}{
+		{name: "method without scope", ctx: context.Background(), method: "/test.Service/Other", wantCode: codes.OK},
+		{name: "no token", ctx: context.Background(), method: method, wantCode: codes.Unauthenticated},
+		{name: "invalid token", ctx: withToken("invalid"), method: method, wantCode: codes.Unauthenticated},
+		{name: "no scope", ctx: withToken("viewer-token"), method: method, wantCode: codes.PermissionDenied},
+		{name: "admin", ctx: withToken("admin-token"), method: method, wantCode: codes.OK, wantCaller: "operator"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := a.authorize(tt.ctx, tt.method)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("authorize() returned %s, want %s", got, tt.wantCode)
+			}
+			if err == nil && CallerFromContext(ctx) != tt.wantCaller {
+				t.Fatalf("caller = %q, want %q", CallerFromContext(ctx), tt.wantCaller)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_NoTokens(t *testing.T) {
+	const method = "/test.Service/Admin"
+	a := New(nil, map[string]Scope{method: ScopeAdmin})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "))
+	if _, err := a.authorize(ctx, method); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("authorize() returned %v, want Unauthenticated", err)
+	}
+}
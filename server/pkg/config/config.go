@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"os"
 	"strconv"
+	"time"
 
 	myshoespb "github.com/whywaita/myshoes/api/proto.go"
 	"github.com/whywaita/myshoes/pkg/datastore"
@@ -20,8 +21,74 @@ const (
 	EnvLXDResourceTypeMapping = "LXD_MULTI_RESOURCE_TYPE_MAPPING"
 	// EnvLXDResourceCachePeriodSec is period of setting LXD resource cache
 	EnvLXDResourceCachePeriodSec = "LXD_MULTI_RESOURCE_CACHE_PERIOD_SEC"
+	// EnvLXDResourceCacheMaxAgeSec is threshold of LXD resource cache age that can be used for allocation
+	EnvLXDResourceCacheMaxAgeSec = "LXD_MULTI_RESOURCE_CACHE_MAX_AGE_SEC"
+	// EnvCircuitBreakerThreshold is number of consecutive failures to open circuit breaker of LXD host
+	EnvCircuitBreakerThreshold = "LXD_MULTI_CIRCUIT_BREAKER_THRESHOLD"
+	// EnvCircuitBreakerCooldownSec is period of skipping LXD host after circuit breaker is opened
+	EnvCircuitBreakerCooldownSec = "LXD_MULTI_CIRCUIT_BREAKER_COOLDOWN_SEC"
+	// EnvAllocateRetryMaxAttempts is max number of attempts to allocate instance
+	EnvAllocateRetryMaxAttempts = "LXD_MULTI_ALLOCATE_RETRY_MAX_ATTEMPTS"
+	// EnvAllocateRetryInitialBackoffMs is wait time before first retry of allocation
+	EnvAllocateRetryInitialBackoffMs = "LXD_MULTI_ALLOCATE_RETRY_INITIAL_BACKOFF_MS"
+	// EnvAllocateRetryMaxBackoffMs is upper limit of wait time between retries of allocation
+	EnvAllocateRetryMaxBackoffMs = "LXD_MULTI_ALLOCATE_RETRY_MAX_BACKOFF_MS"
+	// EnvAllocateRetryBudgetSec is overall time limit of retrying allocation
+	EnvAllocateRetryBudgetSec = "LXD_MULTI_ALLOCATE_RETRY_BUDGET_SEC"
+	// EnvAllocateRetryMaxSetupAttempts is max number of instances that are tried to set up in a request
+	EnvAllocateRetryMaxSetupAttempts = "LXD_MULTI_ALLOCATE_RETRY_MAX_SETUP_ATTEMPTS"
+	// EnvWaitRunnerRegistration enable waiting for the runner to be registered before AddInstance returns
+	EnvWaitRunnerRegistration = "LXD_MULTI_WAIT_RUNNER_REGISTRATION"
+	// EnvWaitRunnerRegistrationTimeoutSec is timeout of waiting for the runner to be registered
+	EnvWaitRunnerRegistrationTimeoutSec = "LXD_MULTI_WAIT_RUNNER_REGISTRATION_TIMEOUT_SEC"
+	// EnvRunnerRegistrationSuccessMarker is text in log of setup script that means the runner is registered
+	EnvRunnerRegistrationSuccessMarker = "LXD_MULTI_RUNNER_REGISTRATION_SUCCESS_MARKER"
+	// EnvRunnerRegistrationFailureMarkers is JSON list of text in log of setup script that means the runner is failed to register
+	EnvRunnerRegistrationFailureMarkers = "LXD_MULTI_RUNNER_REGISTRATION_FAILURE_MARKERS"
+	// EnvLXDHostConcurrency is limit of concurrent LXD API calls per host
+	EnvLXDHostConcurrency = "LXD_MULTI_HOST_CONCURRENCY"
+	// EnvRedisAddr is address of Redis that share state between replicas. If empty, state is kept in memory.
+	EnvRedisAddr = "LXD_MULTI_REDIS_ADDR"
+	// EnvRedisPassword is password of Redis
+	EnvRedisPassword = "LXD_MULTI_REDIS_PASSWORD"
+	// EnvRedisDB is database number of Redis
+	EnvRedisDB = "LXD_MULTI_REDIS_DB"
+	// EnvJournalPath is path of journal file. If empty, journal is disabled.
+	EnvJournalPath = "LXD_MULTI_JOURNAL_PATH"
+	// EnvJournalRetentionDays is period of keeping finished journal entries
+	EnvJournalRetentionDays = "LXD_MULTI_JOURNAL_RETENTION_DAYS"
+	// EnvWebhooks is JSON of webhook sinks that receive lifecycle events
+	EnvWebhooks = "LXD_MULTI_WEBHOOKS"
+	// EnvPrepareSteps is JSON of steps that are executed in allocated instances before setup script
+	EnvPrepareSteps = "LXD_MULTI_PREPARE_STEPS"
+	// EnvQuarantine enable quarantine of instances that are failed to set up, instead of deleting
+	EnvQuarantine = "LXD_MULTI_QUARANTINE"
+	// EnvQuarantineRetentionHours is period of keeping quarantined instances
+	EnvQuarantineRetentionHours = "LXD_MULTI_QUARANTINE_RETENTION_HOURS"
+	// EnvReaper enable reaping leaked instances that are allocated to runners
+	EnvReaper = "LXD_MULTI_REAPER"
+	// EnvReaperDryRun only logs instances to reap
+	EnvReaperDryRun = "LXD_MULTI_REAPER_DRY_RUN"
+	// EnvReaperIntervalSec is interval of reaping
+	EnvReaperIntervalSec = "LXD_MULTI_REAPER_INTERVAL_SEC"
+	// EnvReaperMaxLifetimeHours is max lifetime of instances from allocation
+	EnvReaperMaxLifetimeHours = "LXD_MULTI_REAPER_MAX_LIFETIME_HOURS"
+	// EnvReaperSetupTimeoutSec is period to wait for allocated instances to start setup script
+	EnvReaperSetupTimeoutSec = "LXD_MULTI_REAPER_SETUP_TIMEOUT_SEC"
+	// EnvAPITokens is JSON of tokens that are allowed to call admin methods
+	EnvAPITokens = "LXD_MULTI_API_TOKENS"
 	// EnvPort will listen port
 	EnvPort = "LXD_MULTI_PORT"
+	// EnvMetricsListenAddress is listen address of Prometheus metrics
+	EnvMetricsListenAddress = "LXD_MULTI_METRICS_LISTEN_ADDRESS"
+	// EnvPreDeleteHook is command that is executed in instances before deleting (e.g. removing the runner from GitHub)
+	EnvPreDeleteHook = "LXD_MULTI_PRE_DELETE_HOOK"
+	// EnvPreDeleteHookTimeoutSec is timeout of pre-delete hook
+	EnvPreDeleteHookTimeoutSec = "LXD_MULTI_PRE_DELETE_HOOK_TIMEOUT_SEC"
+	// EnvDeleteTombstoneTTLSec is period of remembering deleted instances, to make retry of deletion succeed
+	EnvDeleteTombstoneTTLSec = "LXD_MULTI_DELETE_TOMBSTONE_TTL_SEC"
+	// EnvShutdownTimeoutSec is deadline of draining in-flight requests on shutdown
+	EnvShutdownTimeoutSec = "LXD_MULTI_SHUTDOWN_TIMEOUT_SEC"
 	// EnvOverCommit will set percent of over commit in CPU
 	EnvOverCommit = "LXD_MULTI_OVER_COMMIT_PERCENT"
 
@@ -120,6 +187,329 @@ func Load() (*HostConfigMap, map[myshoespb.ResourceType]Mapping, map[string]stri
 	return hostConfigs, m, imageAliasMap, periodSec, port, overCommitPercent, &level, nil
 }
 
+// LoadResourceCacheMaxAge load threshold of resource cache age from Environment values.
+// 0 means that cache is used regardless of age.
+func LoadResourceCacheMaxAge() (time.Duration, error) {
+	return loadSecondsEnv(EnvLXDResourceCacheMaxAgeSec, 0)
+}
+
+// LoadCircuitBreaker load config of circuit breaker from Environment values.
+func LoadCircuitBreaker() (int, time.Duration, error) {
+	threshold := 5
+	if env := os.Getenv(EnvCircuitBreakerThreshold); env != "" {
+		t, err := strconv.Atoi(env)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to parse %s, need to int: %w", EnvCircuitBreakerThreshold, err)
+		}
+		threshold = t
+	}
+
+	cooldown, err := loadSecondsEnv(EnvCircuitBreakerCooldownSec, 30*time.Second)
+	if err != nil {
+		return 0, 0, err
+	}
+	return threshold, cooldown, nil
+}
+
+// RetryPolicy is policy of retrying allocation
+type RetryPolicy struct {
+	// MaxAttempts is max number of attempts including the first one
+	MaxAttempts int
+	// InitialBackoff is wait time before first retry, it is doubled for each retry
+	InitialBackoff time.Duration
+	// MaxBackoff is upper limit of wait time between retries
+	MaxBackoff time.Duration
+	// Budget is overall time limit of retrying. 0 means no limit except deadline of request.
+	Budget time.Duration
+	// MaxSetupAttempts is max number of allocated instances that are tried to set up,
+	// the request is retried on another instance if setting up is failed
+	MaxSetupAttempts int
+}
+
+// DefaultRetryPolicy is default policy of retrying allocation
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:      10,
+	InitialBackoff:   500 * time.Millisecond,
+	MaxBackoff:       5 * time.Second,
+	Budget:           60 * time.Second,
+	MaxSetupAttempts: 3,
+}
+
+// LoadRetryPolicy load policy of retrying allocation from Environment values.
+func LoadRetryPolicy() (RetryPolicy, error) {
+	p := DefaultRetryPolicy
+	if env := os.Getenv(EnvAllocateRetryMaxAttempts); env != "" {
+		n, err := strconv.Atoi(env)
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("failed to parse %s, need to int: %w", EnvAllocateRetryMaxAttempts, err)
+		}
+		if n < 1 {
+			return RetryPolicy{}, fmt.Errorf("%s must be greater than 0", EnvAllocateRetryMaxAttempts)
+		}
+		p.MaxAttempts = n
+	}
+	if env := os.Getenv(EnvAllocateRetryMaxSetupAttempts); env != "" {
+		n, err := strconv.Atoi(env)
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("failed to parse %s, need to int: %w", EnvAllocateRetryMaxSetupAttempts, err)
+		}
+		if n < 1 {
+			return RetryPolicy{}, fmt.Errorf("%s must be greater than 0", EnvAllocateRetryMaxSetupAttempts)
+		}
+		p.MaxSetupAttempts = n
+	}
+
+	var err error
+	if p.InitialBackoff, err = loadMillisecondsEnv(EnvAllocateRetryInitialBackoffMs, p.InitialBackoff); err != nil {
+		return RetryPolicy{}, err
+	}
+	if p.MaxBackoff, err = loadMillisecondsEnv(EnvAllocateRetryMaxBackoffMs, p.MaxBackoff); err != nil {
+		return RetryPolicy{}, err
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		return RetryPolicy{}, fmt.Errorf("%s must be greater than or equal to %s", EnvAllocateRetryMaxBackoffMs, EnvAllocateRetryInitialBackoffMs)
+	}
+	if p.Budget, err = loadSecondsEnv(EnvAllocateRetryBudgetSec, p.Budget); err != nil {
+		return RetryPolicy{}, err
+	}
+	return p, nil
+}
+
+// RunnerRegistrationWait is config of waiting for the runner to be registered
+type RunnerRegistrationWait struct {
+	Enabled bool
+	Timeout time.Duration
+	// SuccessMarker is text in log of setup script that means the runner is registered
+	SuccessMarker string
+	// FailureMarkers is list of text in log of setup script that means the runner is failed to register
+	FailureMarkers []string
+}
+
+// LoadRunnerRegistrationWait load config of waiting for the runner to be registered from Environment values.
+func LoadRunnerRegistrationWait() (RunnerRegistrationWait, error) {
+	w := RunnerRegistrationWait{
+		SuccessMarker:  "Listening for Jobs",
+		FailureMarkers: []string{"An error occurred:", "Failed to create a session"},
+	}
+	if env := os.Getenv(EnvWaitRunnerRegistration); env != "" {
+		enabled, err := strconv.ParseBool(env)
+		if err != nil {
+			return RunnerRegistrationWait{}, fmt.Errorf("failed to parse %s, need to bool: %w", EnvWaitRunnerRegistration, err)
+		}
+		w.Enabled = enabled
+	}
+
+	timeout, err := loadSecondsEnv(EnvWaitRunnerRegistrationTimeoutSec, 5*time.Minute)
+	if err != nil {
+		return RunnerRegistrationWait{}, err
+	}
+	w.Timeout = timeout
+
+	if env := os.Getenv(EnvRunnerRegistrationSuccessMarker); env != "" {
+		w.SuccessMarker = env
+	}
+	if env := os.Getenv(EnvRunnerRegistrationFailureMarkers); env != "" {
+		if err := json.Unmarshal([]byte(env), &w.FailureMarkers); err != nil {
+			return RunnerRegistrationWait{}, fmt.Errorf("failed to unmarshal %s: %w", EnvRunnerRegistrationFailureMarkers, err)
+		}
+	}
+	return w, nil
+}
+
+// Quarantine is config of quarantine of instances that are failed to set up
+type Quarantine struct {
+	Enabled bool
+	// Retention is period of keeping quarantined instances. 0 means that they are kept until released.
+	Retention time.Duration
+}
+
+// LoadQuarantine load config of quarantine from Environment values.
+func LoadQuarantine() (Quarantine, error) {
+	q := Quarantine{Retention: 24 * time.Hour}
+	if env := os.Getenv(EnvQuarantine); env != "" {
+		enabled, err := strconv.ParseBool(env)
+		if err != nil {
+			return Quarantine{}, fmt.Errorf("failed to parse %s, need to bool: %w", EnvQuarantine, err)
+		}
+		q.Enabled = enabled
+	}
+	if env := os.Getenv(EnvQuarantineRetentionHours); env != "" {
+		h, err := strconv.ParseUint(env, 10, 64)
+		if err != nil {
+			return Quarantine{}, fmt.Errorf("failed to parse %s, need to uint: %w", EnvQuarantineRetentionHours, err)
+		}
+		q.Retention = time.Duration(h) * time.Hour
+	}
+	return q, nil
+}
+
+// Reaper is config of reaping leaked instances that are allocated to runners
+type Reaper struct {
+	Enabled bool
+	// DryRun only logs instances to reap
+	DryRun   bool
+	Interval time.Duration
+	// MaxLifetime is max lifetime of instances from allocation. 0 means no limit.
+	MaxLifetime time.Duration
+	// SetupTimeout is period to wait for allocated instances to start setup script, and grace period of stopped instances
+	SetupTimeout time.Duration
+}
+
+// LoadReaper load config of reaper from Environment values.
+func LoadReaper() (Reaper, error) {
+	r := Reaper{MaxLifetime: 24 * time.Hour}
+	if env := os.Getenv(EnvReaper); env != "" {
+		enabled, err := strconv.ParseBool(env)
+		if err != nil {
+			return Reaper{}, fmt.Errorf("failed to parse %s, need to bool: %w", EnvReaper, err)
+		}
+		r.Enabled = enabled
+	}
+	if env := os.Getenv(EnvReaperDryRun); env != "" {
+		dryRun, err := strconv.ParseBool(env)
+		if err != nil {
+			return Reaper{}, fmt.Errorf("failed to parse %s, need to bool: %w", EnvReaperDryRun, err)
+		}
+		r.DryRun = dryRun
+	}
+	if env := os.Getenv(EnvReaperMaxLifetimeHours); env != "" {
+		h, err := strconv.ParseUint(env, 10, 64)
+		if err != nil {
+			return Reaper{}, fmt.Errorf("failed to parse %s, need to uint: %w", EnvReaperMaxLifetimeHours, err)
+		}
+		r.MaxLifetime = time.Duration(h) * time.Hour
+	}
+
+	var err error
+	r.Interval, err = loadSecondsEnv(EnvReaperIntervalSec, 5*time.Minute)
+	if err != nil {
+		return Reaper{}, err
+	}
+	if r.Interval == 0 {
+		return Reaper{}, fmt.Errorf("%s must be greater than 0", EnvReaperIntervalSec)
+	}
+	r.SetupTimeout, err = loadSecondsEnv(EnvReaperSetupTimeoutSec, 30*time.Minute)
+	if err != nil {
+		return Reaper{}, err
+	}
+	if r.SetupTimeout == 0 {
+		return Reaper{}, fmt.Errorf("%s must be greater than 0", EnvReaperSetupTimeoutSec)
+	}
+	return r, nil
+}
+
+// LoadHostConcurrency load limit of concurrent LXD API calls per host from Environment values.
+func LoadHostConcurrency() (int, error) {
+	env := os.Getenv(EnvLXDHostConcurrency)
+	if env == "" {
+		return 10, nil
+	}
+	n, err := strconv.Atoi(env)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s, need to int: %w", EnvLXDHostConcurrency, err)
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("%s must be greater than 0", EnvLXDHostConcurrency)
+	}
+	return n, nil
+}
+
+// LoadRedis load config of Redis from Environment values.
+// Empty addr means that Redis is not used.
+func LoadRedis() (string, string, int, error) {
+	addr := os.Getenv(EnvRedisAddr)
+	password := os.Getenv(EnvRedisPassword)
+	db := 0
+	if env := os.Getenv(EnvRedisDB); env != "" {
+		d, err := strconv.Atoi(env)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("failed to parse %s, need to int: %w", EnvRedisDB, err)
+		}
+		db = d
+	}
+	return addr, password, db, nil
+}
+
+// LoadJournal load config of journal from Environment values.
+// Empty path means that journal is disabled.
+func LoadJournal() (string, time.Duration, error) {
+	path := os.Getenv(EnvJournalPath)
+	days := uint64(30)
+	if env := os.Getenv(EnvJournalRetentionDays); env != "" {
+		d, err := strconv.ParseUint(env, 10, 64)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to parse %s, need to uint: %w", EnvJournalRetentionDays, err)
+		}
+		days = d
+	}
+	return path, time.Duration(days) * 24 * time.Hour, nil
+}
+
+// LoadShutdownTimeout load deadline of draining in-flight requests on shutdown from Environment values.
+func LoadShutdownTimeout() (time.Duration, error) {
+	return loadSecondsEnv(EnvShutdownTimeoutSec, 30*time.Second)
+}
+
+// PreDeleteHook is config of command that is executed in instances before deleting
+type PreDeleteHook struct {
+	// Command is executed by /bin/sh -c. Empty means disabled.
+	Command string
+	Timeout time.Duration
+}
+
+// LoadPreDeleteHook load config of pre-delete hook from Environment values.
+func LoadPreDeleteHook() (PreDeleteHook, error) {
+	timeout, err := loadSecondsEnv(EnvPreDeleteHookTimeoutSec, 30*time.Second)
+	if err != nil {
+		return PreDeleteHook{}, err
+	}
+	command := os.Getenv(EnvPreDeleteHook)
+	if command != "" && timeout == 0 {
+		return PreDeleteHook{}, fmt.Errorf("%s must be positive if %s is set", EnvPreDeleteHookTimeoutSec, EnvPreDeleteHook)
+	}
+	return PreDeleteHook{
+		Command: command,
+		Timeout: timeout,
+	}, nil
+}
+
+// LoadDeleteTombstoneTTL load period of remembering deleted instances from Environment values. 0 means disabled.
+func LoadDeleteTombstoneTTL() (time.Duration, error) {
+	return loadSecondsEnv(EnvDeleteTombstoneTTLSec, 10*time.Minute)
+}
+
+// LoadMetricsListenAddress load listen address of Prometheus metrics from Environment values
+func LoadMetricsListenAddress() string {
+	if addr := os.Getenv(EnvMetricsListenAddress); addr != "" {
+		return addr
+	}
+	return ":9090"
+}
+
+func loadSecondsEnv(name string, def time.Duration) (time.Duration, error) {
+	env := os.Getenv(name)
+	if env == "" {
+		return def, nil
+	}
+	sec, err := strconv.ParseUint(env, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s, need to uint: %w", name, err)
+	}
+	return time.Duration(sec) * time.Second, nil
+}
+
+func loadMillisecondsEnv(name string, def time.Duration) (time.Duration, error) {
+	env := os.Getenv(name)
+	if env == "" {
+		return def, nil
+	}
+	ms, err := strconv.ParseUint(env, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s, need to uint: %w", name, err)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
 func readResourceTypeMapping(env string) (map[myshoespb.ResourceType]Mapping, error) {
 	var mapping []Mapping
 	if err := json.Unmarshal([]byte(env), &mapping); err != nil {
@@ -0,0 +1,385 @@
+// Package journal provides on-disk journal of allocation and deletion of instances.
+package journal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Operation is kind of operation
+type Operation string
+
+const (
+	// OperationAllocate is allocation of instance by AddInstance
+	OperationAllocate Operation = "allocate"
+	// OperationDelete is deletion of instance by DeleteInstance
+	OperationDelete Operation = "delete"
+)
+
+// Outcome is result of operation
+type Outcome string
+
+const (
+	// OutcomePending is operation that is not finished yet
+	OutcomePending Outcome = "pending"
+	// OutcomeSucceeded is operation that is succeeded
+	OutcomeSucceeded Outcome = "succeeded"
+	// OutcomeFailed is operation that is failed
+	OutcomeFailed Outcome = "failed"
+	// OutcomeInterrupted is operation that is not finished because server is stopped
+	OutcomeInterrupted Outcome = "interrupted"
+)
+
+// Entry is record of operation
+type Entry struct {
+	ID        uint64    `json:"id"`
+	Operation Operation `json:"operation"`
+
+	RunnerName   string `json:"runner_name"`
+	Host         string `json:"host"`
+	InstanceName string `json:"instance_name"`
+	ImageAlias   string `json:"image_alias"`
+	ResourceType string `json:"resource_type"`
+
+	StartedAt time.Time `json:"started_at"`
+	// AllocatedAt is the time that instance is allocated to runner, only for OperationAllocate
+	AllocatedAt time.Time `json:"allocated_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+
+	Outcome Outcome `json:"outcome"`
+	// Message is detail of outcome, e.g. error message
+	Message string `json:"message"`
+}
+
+// Query is condition of List
+type Query struct {
+	// RunnerName filters entries by runner name if not empty
+	RunnerName string
+	// InstanceName filters entries by instance name if not empty
+	InstanceName string
+	// Since filters entries that started at or after Since if not zero
+	Since time.Time
+	// Until filters entries that started before Until if not zero
+	Until time.Time
+	// Limit is max number of entries, newest entries are returned. 0 is unlimited.
+	Limit int
+}
+
+func (q Query) match(e Entry) bool {
+	if q.RunnerName != "" && e.RunnerName != q.RunnerName {
+		return false
+	}
+	if q.InstanceName != "" && e.InstanceName != q.InstanceName {
+		return false
+	}
+	if !q.Since.IsZero() && e.StartedAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.StartedAt.Before(q.Until) {
+		return false
+	}
+	return true
+}
+
+var (
+	bucketEntries = []byte("entries")
+	// bucketInstances is index of instance name to IDs of entries, key is instance name, NUL and ID
+	bucketInstances = []byte("instances")
+
+	// ErrEntryNotFound is error for entry is not found
+	ErrEntryNotFound = errors.New("journal entry is not found")
+)
+
+// Journal is on-disk journal backed by bbolt.
+// A nil *Journal is valid and records nothing, it is used when journal is disabled.
+type Journal struct {
+	db *bolt.DB
+}
+
+// Open open journal file. The file is created if not exists.
+func Open(path string) (*Journal, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal file: %w", err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(bucketEntries); err != nil {
+			return err
+		}
+		if tx.Bucket(bucketInstances) != nil {
+			return nil
+		}
+		// journal that is created by older version has no index
+		return buildInstanceIndex(tx)
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create bucket: %w", err)
+	}
+
+	return &Journal{db: db}, nil
+}
+
+// Close close journal file
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+	return j.db.Close()
+}
+
+func itob(id uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	return b
+}
+
+func instanceIndexKey(instanceName string, id uint64) []byte {
+	return append([]byte(instanceName+"\x00"), itob(id)...)
+}
+
+func buildInstanceIndex(tx *bolt.Tx) error {
+	idx, err := tx.CreateBucket(bucketInstances)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(bucketEntries).ForEach(func(k, v []byte) error {
+		var e Entry
+		if err := json.Unmarshal(v, &e); err != nil {
+			return fmt.Errorf("failed to unmarshal entry: %w", err)
+		}
+		if e.InstanceName == "" {
+			return nil
+		}
+		return idx.Put(instanceIndexKey(e.InstanceName, e.ID), nil)
+	})
+}
+
+// updateInstanceIndex replace index of the entry from oldName to newName
+func updateInstanceIndex(tx *bolt.Tx, id uint64, oldName, newName string) error {
+	if oldName == newName {
+		return nil
+	}
+	idx := tx.Bucket(bucketInstances)
+	if oldName != "" {
+		if err := idx.Delete(instanceIndexKey(oldName, id)); err != nil {
+			return fmt.Errorf("failed to delete index: %w", err)
+		}
+	}
+	if newName != "" {
+		if err := idx.Put(instanceIndexKey(newName, id), nil); err != nil {
+			return fmt.Errorf("failed to put index: %w", err)
+		}
+	}
+	return nil
+}
+
+func put(b *bolt.Bucket, e Entry) error {
+	v, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal entry: %w", err)
+	}
+	return b.Put(itob(e.ID), v)
+}
+
+// Begin record new pending entry and return its ID
+func (j *Journal) Begin(e Entry) (uint64, error) {
+	if j == nil {
+		return 0, nil
+	}
+
+	err := j.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketEntries)
+		id, err := b.NextSequence()
+		if err != nil {
+			return fmt.Errorf("failed to get next sequence: %w", err)
+		}
+		e.ID = id
+		if e.StartedAt.IsZero() {
+			e.StartedAt = time.Now()
+		}
+		e.Outcome = OutcomePending
+		if err := put(b, e); err != nil {
+			return err
+		}
+		return updateInstanceIndex(tx, e.ID, "", e.InstanceName)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin entry: %w", err)
+	}
+	return e.ID, nil
+}
+
+// Update update the entry by fn
+func (j *Journal) Update(id uint64, fn func(e *Entry)) error {
+	if j == nil {
+		return nil
+	}
+
+	err := j.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketEntries)
+		v := b.Get(itob(id))
+		if v == nil {
+			return ErrEntryNotFound
+		}
+		var e Entry
+		if err := json.Unmarshal(v, &e); err != nil {
+			return fmt.Errorf("failed to unmarshal entry: %w", err)
+		}
+		oldName := e.InstanceName
+		fn(&e)
+		e.ID = id
+		if err := put(b, e); err != nil {
+			return err
+		}
+		return updateInstanceIndex(tx, id, oldName, e.InstanceName)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update entry: %w", err)
+	}
+	return nil
+}
+
+// Finish set outcome of the entry. The outcome is failed if err is not nil.
+func (j *Journal) Finish(id uint64, err error) error {
+	return j.Update(id, func(e *Entry) {
+		e.FinishedAt = time.Now()
+		if err != nil {
+			e.Outcome = OutcomeFailed
+			e.Message = err.Error()
+			return
+		}
+		e.Outcome = OutcomeSucceeded
+	})
+}
+
+// List return entries that match query in order of ID
+func (j *Journal) List(q Query) ([]Entry, error) {
+	if j == nil {
+		return nil, nil
+	}
+
+	var entries []Entry
+	err := j.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketEntries)
+		// add e and return true if limit is reached
+		add := func(v []byte) (bool, error) {
+			var e Entry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return false, fmt.Errorf("failed to unmarshal entry: %w", err)
+			}
+			if !q.match(e) {
+				return false, nil
+			}
+			entries = append(entries, e)
+			return q.Limit > 0 && len(entries) >= q.Limit, nil
+		}
+
+		if q.InstanceName != "" {
+			// only entries of the instance are read by index
+			ids := instanceEntryIDs(tx, q.InstanceName)
+			for i := len(ids) - 1; i >= 0; i-- {
+				v := b.Get(ids[i])
+				if v == nil {
+					continue
+				}
+				if done, err := add(v); err != nil || done {
+					return err
+				}
+			}
+			return nil
+		}
+
+		c := b.Cursor()
+		// iterate from newest to apply limit
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			if done, err := add(v); err != nil || done {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entries: %w", err)
+	}
+
+	// reverse to order of ID
+	for i, k := 0, len(entries)-1; i < k; i, k = i+1, k-1 {
+		entries[i], entries[k] = entries[k], entries[i]
+	}
+	return entries, nil
+}
+
+// instanceEntryIDs return keys of entries of the instance in order of ID
+func instanceEntryIDs(tx *bolt.Tx, instanceName string) [][]byte {
+	prefix := []byte(instanceName + "\x00")
+	var ids [][]byte
+	c := tx.Bucket(bucketInstances).Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		ids = append(ids, bytes.Clone(k[len(prefix):]))
+	}
+	return ids
+}
+
+// ListPending return entries that are not finished
+func (j *Journal) ListPending() ([]Entry, error) {
+	entries, err := j.List(Query{})
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Entry
+	for _, e := range entries {
+		if e.Outcome == OutcomePending {
+			pending = append(pending, e)
+		}
+	}
+	return pending, nil
+}
+
+// Prune delete finished entries that started before t, and return number of deleted entries
+func (j *Journal) Prune(t time.Time) (int, error) {
+	if j == nil {
+		return 0, nil
+	}
+
+	var pruned []Entry
+	err := j.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketEntries)
+		c := b.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var e Entry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return fmt.Errorf("failed to unmarshal entry: %w", err)
+			}
+			if !e.StartedAt.Before(t) {
+				// entries are ordered by ID, so newer entries are remaining
+				break
+			}
+			if e.Outcome == OutcomePending {
+				continue
+			}
+			pruned = append(pruned, e)
+		}
+
+		// delete after iteration, deleting in cursor loop may skip entries
+		for _, e := range pruned {
+			if err := b.Delete(itob(e.ID)); err != nil {
+				return fmt.Errorf("failed to delete entry: %w", err)
+			}
+			if err := updateInstanceIndex(tx, e.ID, e.InstanceName, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune entries: %w", err)
+	}
+	return len(pruned), nil
+}
@@ -0,0 +1,209 @@
+package journal
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func newTestJournal(t *testing.T) *Journal {
+	t.Helper()
+
+	j, err := Open(filepath.Join(t.TempDir(), "journal.db"))
+	if err != nil {
+		t.Fatalf("failed to open journal: %+v", err)
+	}
+	t.Cleanup(func() { j.Close() })
+	return j
+}
+
+func TestJournal_BeginFinish(t *testing.T) {
+	j := newTestJournal(t)
+
+	id, err := j.Begin(Entry{Operation: OperationAllocate, RunnerName: "runner-1"})
+	if err != nil {
+		t.Fatalf("failed to begin: %+v", err)
+	}
+	if err := j.Update(id, func(e *Entry) {
+		e.Host = "host-a"
+		e.InstanceName = "instance-1"
+	}); err != nil {
+		t.Fatalf("failed to update: %+v", err)
+	}
+
+	pending, err := j.ListPending()
+	if err != nil {
+		t.Fatalf("failed to list pending: %+v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != id || pending[0].Host != "host-a" {
+		t.Fatalf("ListPending() = %+v, want the begun entry", pending)
+	}
+
+	if err := j.Finish(id, errors.New("setup failed")); err != nil {
+		t.Fatalf("failed to finish: %+v", err)
+	}
+	entries, err := j.List(Query{RunnerName: "runner-1"})
+	if err != nil {
+		t.Fatalf("failed to list: %+v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("List() returned %d entries, want 1", len(entries))
+	}
+	got := entries[0]
+	if got.Outcome != OutcomeFailed || got.Message != "setup failed" || got.FinishedAt.IsZero() || got.InstanceName != "instance-1" {
+		t.Errorf("finished entry = %+v", got)
+	}
+	if pending, _ := j.ListPending(); len(pending) != 0 {
+		t.Errorf("ListPending() = %+v after finish, want empty", pending)
+	}
+
+	if err := j.Update(100, func(*Entry) {}); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("Update() for unknown id returned %v, want ErrEntryNotFound", err)
+	}
+}
+
+func TestJournal_List(t *testing.T) {
+	j := newTestJournal(t)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, runner := range []string{"runner-1", "runner-2", "runner-1", "runner-3"} {
+		if _, err := j.Begin(Entry{RunnerName: runner, InstanceName: "instance-" + runner, StartedAt: base.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatalf("failed to begin: %+v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []uint64
+	}{
+		{name: "all", query: Query{}, want: []uint64{1, 2, 3, 4}},
+		{name: "runner name", query: Query{RunnerName: "runner-1"}, want: []uint64{1, 3}},
+		{name: "instance name", query: Query{InstanceName: "instance-runner-2"}, want: []uint64{2}},
+		{name: "time range", query: Query{Since: base.Add(1 * time.Hour), Until: base.Add(3 * time.Hour)}, want: []uint64{2, 3}},
+		{name: "limit returns newest", query: Query{Limit: 2}, want: []uint64{3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := j.List(tt.query)
+			if err != nil {
+				t.Fatalf("failed to list: %+v", err)
+			}
+			var got []uint64
+			for _, e := range entries {
+				got = append(got, e.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("List() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("List() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestJournal_Prune(t *testing.T) {
+	j := newTestJournal(t)
+	old := time.Now().Add(-48 * time.Hour)
+
+	finished1, _ := j.Begin(Entry{StartedAt: old})
+	pending, _ := j.Begin(Entry{StartedAt: old})
+	finished2, _ := j.Begin(Entry{StartedAt: old})
+	recent, _ := j.Begin(Entry{})
+	for _, id := range []uint64{finished1, finished2, recent} {
+		if err := j.Finish(id, nil); err != nil {
+			t.Fatalf("failed to finish: %+v", err)
+		}
+	}
+
+	deleted, err := j.Prune(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("failed to prune: %+v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("Prune() deleted %d entries, want 2", deleted)
+	}
+
+	entries, _ := j.List(Query{})
+	if len(entries) != 2 || entries[0].ID != pending || entries[1].ID != recent {
+		t.Errorf("remaining entries = %+v, want pending and recent entry", entries)
+	}
+}
+
+func TestJournal_Nil(t *testing.T) {
+	var j *Journal
+	id, err := j.Begin(Entry{})
+	if err != nil || id != 0 {
+		t.Errorf("Begin() on nil journal = %d, %v", id, err)
+	}
+	if err := j.Finish(id, nil); err != nil {
+		t.Errorf("Finish() on nil journal = %v", err)
+	}
+}
+
+func TestJournal_InstanceIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.db")
+	j, err := Open(path)
+	if err != nil {
+		t.Fatalf("failed to open journal: %+v", err)
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	pruned, _ := j.Begin(Entry{InstanceName: "instance-1", StartedAt: old})
+	if err := j.Finish(pruned, nil); err != nil {
+		t.Fatalf("failed to finish: %+v", err)
+	}
+	// instance name that has instance-1 as prefix must not be matched
+	if _, err := j.Begin(Entry{InstanceName: "instance-10"}); err != nil {
+		t.Fatalf("failed to begin: %+v", err)
+	}
+	renamed, _ := j.Begin(Entry{InstanceName: "instance-2"})
+	if err := j.Update(renamed, func(e *Entry) { e.InstanceName = "instance-1" }); err != nil {
+		t.Fatalf("failed to update: %+v", err)
+	}
+
+	assertIDs := func(instanceName string, want ...uint64) {
+		t.Helper()
+		entries, err := j.List(Query{InstanceName: instanceName})
+		if err != nil {
+			t.Fatalf("failed to list: %+v", err)
+		}
+		var got []uint64
+		for _, e := range entries {
+			got = append(got, e.ID)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("List(%q) = %v, want %v", instanceName, got, want)
+		}
+	}
+	assertIDs("instance-1", pruned, renamed)
+	assertIDs("instance-2")
+
+	if _, err := j.Prune(time.Now().Add(-24 * time.Hour)); err != nil {
+		t.Fatalf("failed to prune: %+v", err)
+	}
+	assertIDs("instance-1", renamed)
+
+	// journal that is created by older version has no index, it is built on open
+	j.Close()
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatalf("failed to open db: %+v", err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error { return tx.DeleteBucket(bucketInstances) }); err != nil {
+		t.Fatalf("failed to delete index: %+v", err)
+	}
+	db.Close()
+	j, err = Open(path)
+	if err != nil {
+		t.Fatalf("failed to open journal: %+v", err)
+	}
+	t.Cleanup(func() { j.Close() })
+	assertIDs("instance-1", renamed)
+}
@@ -0,0 +1,200 @@
+package lxdclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+)
+
+// CircuitState is state of circuit breaker for LXD host
+type CircuitState int
+
+const (
+	// CircuitClosed is normal state, API calls to the host are allowed
+	CircuitClosed CircuitState = iota
+	// CircuitOpen is state that the host is skipped until cooldown is passed
+	CircuitOpen
+	// CircuitHalfOpen is state that only one probe to the host is allowed
+	CircuitHalfOpen
+)
+
+// String return name of state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("unknown(%d)", int(s))
+}
+
+const (
+	defaultCircuitBreakerThreshold = 5
+	defaultCircuitBreakerCooldown  = 30 * time.Second
+)
+
+var (
+	// circuitBreakers is map of circuit breaker
+	// key: lxdhost value: *circuitBreaker
+	circuitBreakers sync.Map
+
+	circuitBreakerThreshold atomic.Int64
+	circuitBreakerCooldown  atomic.Int64
+)
+
+func init() {
+	SetCircuitBreakerConfig(defaultCircuitBreakerThreshold, defaultCircuitBreakerCooldown)
+}
+
+// SetCircuitBreakerConfig set config of circuit breaker.
+// The circuit of a host is opened after threshold consecutive failures, and a probe is allowed after cooldown.
+// threshold <= 0 disables circuit breaker.
+func SetCircuitBreakerConfig(threshold int, cooldown time.Duration) {
+	circuitBreakerThreshold.Store(int64(threshold))
+	circuitBreakerCooldown.Store(int64(cooldown))
+}
+
+// CircuitStateObserver is a function type for observing that host becomes unreachable (open) or recovered (closed)
+type CircuitStateObserver func(host string, state CircuitState)
+
+var circuitStateObserver atomic.Value
+
+// SetCircuitStateObserver sets the observer function for transition of circuit state
+func SetCircuitStateObserver(observer CircuitStateObserver) {
+	circuitStateObserver.Store(observer)
+}
+
+func observeCircuitState(host string, state CircuitState) {
+	if observer := circuitStateObserver.Load(); observer != nil {
+		observer.(CircuitStateObserver)(host, state)
+	}
+}
+
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	state    CircuitState
+	failures int
+	// changedAt is the time of the latest transition to open or half-open
+	changedAt time.Time
+}
+
+func loadCircuitBreaker(host string) *circuitBreaker {
+	v, _ := circuitBreakers.LoadOrStore(host, &circuitBreaker{})
+	return v.(*circuitBreaker)
+}
+
+// AllowRequest return whether API calls to the host are allowed.
+// If cooldown is passed in open state, the circuit becomes half-open and only the caller is allowed as a probe.
+func AllowRequest(host string) bool {
+	if circuitBreakerThreshold.Load() <= 0 {
+		return true
+	}
+
+	cb := loadCircuitBreaker(host)
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cooldown := time.Duration(circuitBreakerCooldown.Load())
+	switch cb.state {
+	case CircuitOpen:
+		if time.Since(cb.changedAt) < cooldown {
+			return false
+		}
+		slog.Info("circuit breaker is half-open, probe host", "host", host)
+		cb.state = CircuitHalfOpen
+		cb.changedAt = time.Now()
+		return true
+	case CircuitHalfOpen:
+		// allow another probe if the result of previous probe is never recorded
+		if time.Since(cb.changedAt) < cooldown {
+			return false
+		}
+		cb.changedAt = time.Now()
+		return true
+	}
+	return true
+}
+
+// GetCircuitState return current state of circuit breaker for the host
+func GetCircuitState(host string) CircuitState {
+	v, ok := circuitBreakers.Load(host)
+	if !ok {
+		return CircuitClosed
+	}
+	cb := v.(*circuitBreaker)
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// RecordAPIResult record a result of API call to the host. ctx is context of the caller of API.
+// Only transport errors are counted as failure, errors returned by LXD API (e.g. not found) are not.
+func RecordAPIResult(ctx context.Context, host string, err error) {
+	if circuitBreakerThreshold.Load() <= 0 {
+		return
+	}
+	if err != nil && !isTransportError(ctx, err) {
+		return
+	}
+
+	if state, changed := recordAPIResult(host, err); changed {
+		observeCircuitState(host, state)
+	}
+}
+
+// recordAPIResult update circuit breaker, and return new state and whether the host becomes unreachable or recovered
+func recordAPIResult(host string, err error) (CircuitState, bool) {
+	cb := loadCircuitBreaker(host)
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if err == nil {
+		changed := cb.state != CircuitClosed
+		if changed {
+			slog.Info("circuit breaker is closed, host is recovered", "host", host)
+		}
+		cb.state = CircuitClosed
+		cb.failures = 0
+		return cb.state, changed
+	}
+
+	cb.failures++
+	if cb.state == CircuitHalfOpen || (cb.state == CircuitClosed && int64(cb.failures) >= circuitBreakerThreshold.Load()) {
+		slog.Warn("circuit breaker is opened", "host", host, "failures", cb.failures, "err", err.Error())
+		// failed probe in half-open is not a change, host is still unreachable
+		changed := cb.state == CircuitClosed
+		cb.state = CircuitOpen
+		cb.changedAt = time.Now()
+
+		// rebuild the client in next connection
+		deleteConnectedInstance(host)
+		return cb.state, changed
+	}
+	return cb.state, false
+}
+
+func isTransportError(ctx context.Context, err error) bool {
+	if errors.Is(err, context.Canceled) {
+		// caller gave up, not a problem of host
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil {
+		// deadline of the caller is exceeded, the host may be only slow for short deadline
+		return false
+	}
+	if _, ok := api.StatusErrorMatch(err); ok {
+		// LXD API returned response
+		return false
+	}
+	return true
+}
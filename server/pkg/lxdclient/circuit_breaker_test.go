@@ -0,0 +1,165 @@
+package lxdclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	SetCircuitBreakerConfig(3, 50*time.Millisecond)
+	t.Cleanup(func() { SetCircuitBreakerConfig(defaultCircuitBreakerThreshold, defaultCircuitBreakerCooldown) })
+
+	host := "https://test-circuit-breaker:8443"
+	errTransport := errors.New("connection refused")
+
+	for range 2 {
+		RecordAPIResult(context.Background(), host, errTransport)
+	}
+	if got := GetCircuitState(host); got != CircuitClosed {
+		t.Fatalf("state after 2 failures = %s, want %s", got, CircuitClosed)
+	}
+
+	// errors from LXD API are not counted
+	RecordAPIResult(context.Background(), host, api.StatusErrorf(http.StatusNotFound, "Instance not found"))
+	if got := GetCircuitState(host); got != CircuitClosed {
+		t.Fatalf("state after API error = %s, want %s", got, CircuitClosed)
+	}
+
+	RecordAPIResult(context.Background(), host, errTransport)
+	if got := GetCircuitState(host); got != CircuitOpen {
+		t.Fatalf("state after 3 failures = %s, want %s", got, CircuitOpen)
+	}
+	if AllowRequest(host) {
+		t.Fatalf("AllowRequest() = true in cooldown, want false")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !AllowRequest(host) {
+		t.Fatalf("AllowRequest() = false after cooldown, want true as a probe")
+	}
+	if got := GetCircuitState(host); got != CircuitHalfOpen {
+		t.Fatalf("state after cooldown = %s, want %s", got, CircuitHalfOpen)
+	}
+	if AllowRequest(host) {
+		t.Fatalf("AllowRequest() = true while probing, want false")
+	}
+
+	// failed probe opens circuit again
+	RecordAPIResult(context.Background(), host, errTransport)
+	if got := GetCircuitState(host); got != CircuitOpen {
+		t.Fatalf("state after failed probe = %s, want %s", got, CircuitOpen)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !AllowRequest(host) {
+		t.Fatalf("AllowRequest() = false after cooldown, want true as a probe")
+	}
+	RecordAPIResult(context.Background(), host, nil)
+	if got := GetCircuitState(host); got != CircuitClosed {
+		t.Fatalf("state after succeeded probe = %s, want %s", got, CircuitClosed)
+	}
+	if !AllowRequest(host) {
+		t.Fatalf("AllowRequest() = false after recovered, want true")
+	}
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	SetCircuitBreakerConfig(0, time.Minute)
+	t.Cleanup(func() { SetCircuitBreakerConfig(defaultCircuitBreakerThreshold, defaultCircuitBreakerCooldown) })
+
+	host := "https://test-circuit-breaker-disabled:8443"
+	for range 10 {
+		RecordAPIResult(context.Background(), host, errors.New("connection refused"))
+	}
+	if !AllowRequest(host) {
+		t.Errorf("AllowRequest() = false with disabled circuit breaker, want true")
+	}
+}
+
+func TestCircuitStateObserver(t *testing.T) {
+	SetCircuitBreakerConfig(1, time.Millisecond)
+	t.Cleanup(func() { SetCircuitBreakerConfig(defaultCircuitBreakerThreshold, defaultCircuitBreakerCooldown) })
+
+	host := "https://test-circuit-observer:8443"
+	var observed []CircuitState
+	SetCircuitStateObserver(func(h string, state CircuitState) {
+		if h == host {
+			observed = append(observed, state)
+		}
+	})
+	t.Cleanup(func() { SetCircuitStateObserver(func(string, CircuitState) {}) })
+
+	errTransport := errors.New("connection refused")
+	RecordAPIResult(context.Background(), host, errTransport)
+	// failed probe in half-open is not notified again
+	time.Sleep(5 * time.Millisecond)
+	if !AllowRequest(host) {
+		t.Fatalf("AllowRequest() = false after cooldown, want true")
+	}
+	RecordAPIResult(context.Background(), host, errTransport)
+	time.Sleep(5 * time.Millisecond)
+	AllowRequest(host)
+	RecordAPIResult(context.Background(), host, nil)
+
+	want := []CircuitState{CircuitOpen, CircuitClosed}
+	if len(observed) != len(want) || observed[0] != want[0] || observed[1] != want[1] {
+		t.Errorf("observed states = %v, want %v", observed, want)
+	}
+}
+
+func TestIsTransportError(t *testing.T) {
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{
+			name: "connection refused",
+			ctx:  context.Background(),
+			err:  errors.New("connection refused"),
+			want: true,
+		},
+		{
+			name: "LXD API error",
+			ctx:  context.Background(),
+			err:  api.StatusErrorf(http.StatusNotFound, "Instance not found"),
+			want: false,
+		},
+		{
+			name: "canceled by caller",
+			ctx:  context.Background(),
+			err:  context.Canceled,
+			want: false,
+		},
+		{
+			name: "deadline of caller is exceeded",
+			ctx:  expired,
+			err:  fmt.Errorf("get instance: %w", context.DeadlineExceeded),
+			want: false,
+		},
+		{
+			name: "timeout in client",
+			ctx:  context.Background(),
+			err:  fmt.Errorf("get instance: %w", context.DeadlineExceeded),
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransportError(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("isTransportError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
 
 	lxd "github.com/lxc/lxd/client"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
@@ -16,10 +18,71 @@ import (
 
 // LXDHost is client of LXD and host config
 type LXDHost struct {
+	// Client is connection to LXD host that shared by all callers.
+	// (*lxd.ProtocolLXD).WithContext mutates the receiver, so do not call it on Client.
+	// Use Acquire to get a client bound to a context.
 	Client     *lxd.ProtocolLXD
 	HostConfig config.HostConfig
 
-	APICallMutex sync.Mutex
+	semOnce sync.Once
+	sem     *semaphore.Weighted
+}
+
+const defaultHostConcurrency = 10
+
+// hostConcurrency is limit of concurrent API calls per host
+var hostConcurrency atomic.Int64
+
+func init() {
+	SetHostConcurrency(defaultHostConcurrency)
+}
+
+// SetHostConcurrency set limit of concurrent API calls per host.
+// It affects hosts that are connected after calling.
+func SetHostConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	hostConcurrency.Store(int64(n))
+}
+
+// Acquire waits for a free slot of concurrent API calls to the host, and return a client bound to ctx.
+// The caller must call release after finishing API calls.
+func (h *LXDHost) Acquire(ctx context.Context) (lxd.InstanceServer, func(), error) {
+	h.semOnce.Do(func() {
+		h.sem = semaphore.NewWeighted(hostConcurrency.Load())
+	})
+
+	if err := h.sem.Acquire(ctx, 1); err != nil {
+		return nil, nil, fmt.Errorf("failed to wait for API call slot of %s: %w", h.HostConfig.LxdHost, err)
+	}
+	release := func() {
+		h.sem.Release(1)
+	}
+	return withContext(h.Client, ctx), release, nil
+}
+
+// withContext return a copy of client bound to ctx.
+// UseProject return a new client that shares connection with c, so WithContext does not affect other callers.
+func withContext(c *lxd.ProtocolLXD, ctx context.Context) lxd.InstanceServer {
+	cc := c.UseProject("").(*lxd.ProtocolLXD)
+	return cc.WithContext(ctx)
+}
+
+// WaitOperation waits until op is finished or ctx is done.
+// lxd.Operation.Wait is not support context, so the operation is continued in LXD host even if ctx is done.
+func WaitOperation(ctx context.Context, op lxd.Operation) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- op.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("stop waiting operation %s: %w", op.Get().ID, ctx.Err())
+	}
 }
 
 // ErrLXDHost is error for LXD host
@@ -43,17 +106,21 @@ func ConnectLXDs(ctx context.Context, hostConfigs []config.HostConfig) ([]*LXDHo
 			conn, err := ConnectLXDWithTimeout(ctx, hc.LxdHost, hc.LxdClientCert, hc.LxdClientKey)
 			if err != nil && !errors.Is(err, ErrTimeoutConnectLXD) {
 				l.Warn("failed to connect LXD with timeout (not ErrTimeoutConnectLXD)", "err", err.Error())
+				mu.Lock()
 				errLXDHosts = append(errLXDHosts, ErrLXDHost{
 					HostConfig: hc,
 					Err:        err,
 				})
+				mu.Unlock()
 				return nil
 			} else if errors.Is(err, ErrTimeoutConnectLXD) {
 				l.Warn("failed to connect LXD, So ignore host")
+				mu.Lock()
 				errLXDHosts = append(errLXDHosts, ErrLXDHost{
 					HostConfig: hc,
 					Err:        err,
 				})
+				mu.Unlock()
 				return nil
 			}
 
@@ -101,6 +168,7 @@ func ConnectLXDWithTimeout(ctx context.Context, host, clientCert, clientKey stri
 		InsecureSkipVerify: true,
 	}
 	client, err := lxd.ConnectLXDWithContext(cctx, host, args)
+	RecordAPIResult(ctx, host, err)
 	if err != nil {
 		// if timeout, return ErrTimeoutConnectLXD
 		if errors.Is(err, context.DeadlineExceeded) {
@@ -118,9 +186,8 @@ func ConnectLXDWithTimeout(ctx context.Context, host, clientCert, clientKey stri
 	c.WithContext(context.Background())
 
 	result := &LXDHost{
-		Client:       c,
-		HostConfig:   config.HostConfig{LxdHost: host, LxdClientCert: clientCert, LxdClientKey: clientKey},
-		APICallMutex: sync.Mutex{},
+		Client:     c,
+		HostConfig: config.HostConfig{LxdHost: host, LxdClientCert: clientCert, LxdClientKey: clientKey},
 	}
 	storeConnectedInstance(host, result)
 	return result, nil
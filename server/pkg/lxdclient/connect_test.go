@@ -2,104 +2,161 @@ package lxdclient
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	lxd "github.com/lxc/lxd/client"
+	"github.com/lxc/lxd/shared/api"
+
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
 )
 
-func newTestHost() *LXDHost {
+// newTestHost create LXDHost that connect to fake LXD API.
+// The fake API responds GET /1.0/instances after delay.
+func newTestHost(t *testing.T, delay time.Duration) *LXDHost {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"type": "sync", "status": "Success", "status_code": 200, "metadata": []}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := lxd.ConnectLXD(srv.URL, &lxd.ConnectionArgs{
+		InsecureSkipVerify: true,
+		SkipGetServer:      true,
+	})
+	if err != nil {
+		t.Fatalf("failed to connect fake LXD: %+v", err)
+	}
+
 	return &LXDHost{
-		HostConfig: config.HostConfig{LxdHost: "https://test:8443"},
+		Client:     c.(*lxd.ProtocolLXD),
+		HostConfig: config.HostConfig{LxdHost: srv.URL},
 	}
 }
 
-func TestAPICallMutex_ExclusiveAccess(t *testing.T) {
-	host := newTestHost()
+func TestAcquire_LimitConcurrency(t *testing.T) {
+	SetHostConcurrency(2)
+	t.Cleanup(func() { SetHostConcurrency(defaultHostConcurrency) })
+
+	host := newTestHost(t, 0)
+
+	_, release1, err := host.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("failed to acquire first slot: %+v", err)
+	}
+	_, release2, err := host.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("failed to acquire second slot: %+v", err)
+	}
 
-	host.APICallMutex.Lock()
+	// Third caller should wait until a slot is released
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := host.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected third Acquire to wait and fail with deadline, got %v", err)
+	}
 
-	// Another goroutine should not be able to acquire the mutex
 	acquired := make(chan struct{})
 	go func() {
-		host.APICallMutex.Lock()
+		_, release, err := host.Acquire(context.Background())
+		if err != nil {
+			t.Errorf("failed to acquire after release: %+v", err)
+			return
+		}
+		release()
 		close(acquired)
-		host.APICallMutex.Unlock()
 	}()
 
-	select {
-	case <-acquired:
-		t.Fatal("expected mutex to block second goroutine")
-	case <-time.After(50 * time.Millisecond):
-		// expected: second goroutine is blocked
-	}
-
-	host.APICallMutex.Unlock()
-
-	// Now the second goroutine should acquire the mutex
+	release1()
 	select {
 	case <-acquired:
 		// success
 	case <-time.After(time.Second):
-		t.Fatal("expected second goroutine to acquire mutex after unlock")
+		t.Fatal("expected waiting goroutine to acquire slot after release")
 	}
+	release2()
 }
 
-func TestAPICallMutex_TryLock(t *testing.T) {
-	host := newTestHost()
+func TestAcquire_NoContextRace(t *testing.T) {
+	// This test verifies that concurrent goroutines calling API of the same host
+	// use their own context. A short deadline of one caller must not cancel the others.
+	SetHostConcurrency(10)
+	t.Cleanup(func() { SetHostConcurrency(defaultHostConcurrency) })
 
-	host.APICallMutex.Lock()
+	host := newTestHost(t, 100*time.Millisecond)
 
-	if host.APICallMutex.TryLock() {
-		t.Fatal("expected TryLock to fail when mutex is held")
+	// Clients that acquired earlier must keep their own context
+	shortCtx, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	cShort, releaseShort, err := host.Acquire(shortCtx)
+	if err != nil {
+		t.Fatalf("failed to acquire: %+v", err)
 	}
-
-	host.APICallMutex.Unlock()
-
-	if !host.APICallMutex.TryLock() {
-		t.Fatal("expected TryLock to succeed after unlock")
+	cLong, releaseLong, err := host.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("failed to acquire: %+v", err)
 	}
-	host.APICallMutex.Unlock()
-}
 
-func TestAPICallMutex_NoContextRace(t *testing.T) {
-	// This test verifies that concurrent goroutines accessing the same host
-	// do not race on context when following the correct Mutex pattern.
-	host := newTestHost()
+	if _, err := cShort.GetInstances(api.InstanceTypeAny); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected client with short deadline to fail with deadline, got %v", err)
+	}
+	if _, err := cLong.GetInstances(api.InstanceTypeAny); err != nil {
+		t.Errorf("expected client without deadline to succeed, got %v", err)
+	}
+	releaseShort()
+	releaseLong()
 
 	const goroutines = 10
 	var wg sync.WaitGroup
-	var errorCount atomic.Int32
+	var shortErrors, longErrors atomic.Int32
 
-	for range goroutines {
+	for i := range goroutines {
 		wg.Add(1)
-		go func() {
+		go func(short bool) {
 			defer wg.Done()
 
-			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			d := 5 * time.Second
+			if short {
+				d = 10 * time.Millisecond
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), d)
 			defer cancel()
 
-			host.APICallMutex.Lock()
-			defer host.APICallMutex.Unlock()
-
-			// Verify context is not already canceled when we acquire the mutex
-			if ctx.Err() != nil {
-				errorCount.Add(1)
+			c, release, err := host.Acquire(ctx)
+			if err != nil {
+				t.Errorf("failed to acquire: %+v", err)
 				return
 			}
-
-			// Simulate some work
-			time.Sleep(5 * time.Millisecond)
-		}()
+			defer release()
+
+			if _, err := c.GetInstances(api.InstanceTypeAny); err != nil {
+				if short {
+					shortErrors.Add(1)
+				} else {
+					longErrors.Add(1)
+				}
+			}
+		}(i%2 == 0)
 	}
 
 	wg.Wait()
 
-	// With 10 goroutines each holding the mutex for 5ms, some may timeout (50ms total > 100ms timeout).
-	// The important thing is that at least some goroutines succeed.
-	if errorCount.Load() == int32(goroutines) {
-		t.Fatal("all goroutines had context errors, expected at least some to succeed")
+	if got := shortErrors.Load(); got != goroutines/2 {
+		t.Errorf("callers with short deadline failed %d times, want %d", got, goroutines/2)
+	}
+	if got := longErrors.Load(); got != 0 {
+		t.Errorf("callers with long deadline failed %d times, want 0 (context leaked from other callers)", got)
 	}
 }
@@ -1,12 +1,19 @@
 package lxdclient
 
 import (
+	"context"
 	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/tracing"
 )
 
-// APIMetricsObserver is a function type for observing LXD API call metrics
-type APIMetricsObserver func(host, method string, duration time.Duration, err error)
+// APIMetricsObserver is a function type for observing LXD API call metrics.
+// ctx has the span of the call, it is used for exemplars.
+type APIMetricsObserver func(ctx context.Context, host, method string, duration time.Duration, err error)
 
 var apiMetricsObserver atomic.Value
 
@@ -15,9 +22,31 @@ func SetAPIMetricsObserver(observer APIMetricsObserver) {
 	apiMetricsObserver.Store(observer)
 }
 
-// observeAPICall records metrics for an LXD API call if an observer is set
-func observeAPICall(host, method string, startTime time.Time, err error) {
+// StartAPISpan starts a span of an LXD API call
+func StartAPISpan(ctx context.Context, host, method string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "lxd."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("lxd.host", host),
+			attribute.String("lxd.method", method),
+		),
+	)
+}
+
+// startAPICall starts a span of an LXD API call, and return a function that records the result
+func startAPICall(ctx context.Context, host, method string) func(err error) {
+	startTime := time.Now()
+	ctx, span := StartAPISpan(ctx, host, method)
+	return func(err error) {
+		observeAPICall(ctx, host, method, startTime, err)
+		tracing.End(span, err)
+	}
+}
+
+// observeAPICall records metrics for an LXD API call if an observer is set, and records the result to circuit breaker
+func observeAPICall(ctx context.Context, host, method string, startTime time.Time, err error) {
+	RecordAPIResult(ctx, host, err)
 	if observer := apiMetricsObserver.Load(); observer != nil {
-		observer.(APIMetricsObserver)(host, method, time.Since(startTime), err)
+		observer.(APIMetricsObserver)(ctx, host, method, time.Since(startTime), err)
 	}
 }
@@ -0,0 +1,53 @@
+package lxdclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStartAPICall(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	var observed trace.SpanContext
+	SetAPIMetricsObserver(func(ctx context.Context, host, method string, duration time.Duration, err error) {
+		observed = trace.SpanContextFromContext(ctx)
+	})
+	t.Cleanup(func() {
+		SetAPIMetricsObserver(func(context.Context, string, string, time.Duration, error) {})
+	})
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	done := startAPICall(ctx, "https://test-start-api-call:8443", "GetInstances")
+	done(errors.New("connection refused"))
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("len(spans) = %d, want 2", len(spans))
+	}
+	got := spans[0]
+	if got.Name() != "lxd.GetInstances" {
+		t.Fatalf("span name = %q, want %q", got.Name(), "lxd.GetInstances")
+	}
+	if got.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("parent of span = %s, want %s", got.Parent().SpanID(), parent.SpanContext().SpanID())
+	}
+	if got.Status().Code != codes.Error {
+		t.Fatalf("span status = %s, want %s", got.Status().Code, codes.Error)
+	}
+	if observed.SpanID() != got.SpanContext().SpanID() {
+		t.Fatalf("observer got span %s, want %s", observed.SpanID(), got.SpanContext().SpanID())
+	}
+}
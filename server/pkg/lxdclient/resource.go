@@ -35,6 +35,12 @@ const (
 	ConfigKeyRunnerName = "user.myshoes_runner_name"
 	// ConfigKeyAllocatedAt is key of allocated at
 	ConfigKeyAllocatedAt = "user.myshoes_allocated_at"
+	// ConfigKeySetupState is key of last completed step of setting up the allocated instance
+	ConfigKeySetupState = "user.myshoes_setup_state"
+	// ConfigKeyQuarantined is key of reason why the instance is quarantined
+	ConfigKeyQuarantined = "user.myshoes_quarantined"
+	// ConfigKeyQuarantinedAt is key of quarantined at
+	ConfigKeyQuarantinedAt = "user.myshoes_quarantined_at"
 )
 
 // GetCPUOverCommitPercent calculate percent of over commit
@@ -45,23 +51,28 @@ func GetCPUOverCommitPercent(in Resource) uint64 {
 // GetResource get Resource
 func GetResource(ctx context.Context, hostConfig config.HostConfig, logger *slog.Logger) (*Resource, error) {
 	status, err := GetStatusCache(hostConfig.LxdHost)
-	if err == nil {
+	switch {
+	case err == nil && !status.IsStale():
 		// found from cache
 		return &status.Resource, nil
-	}
-	if !errors.Is(err, ErrCacheNotFound) {
+	case err == nil:
+		logger.Warn("status in cache is too old, so scrape from lxd", "age", status.Age().String())
+	case errors.Is(err, ErrCacheNotFound):
+		logger.Warn("failed to get status from cache, so scrape from lxd")
+	default:
 		return nil, fmt.Errorf("failed to get status from cache: %w", err)
 	}
 
-	logger.Warn("failed to get status from cache, so scrape from lxd")
-
-	r, _, err := GetResourceFromLXD(ctx, hostConfig, logger)
+	r, hostname, err := GetResourceFromLXD(ctx, hostConfig, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get resource from lxd: %w", err)
 	}
 	s := LXDStatus{
-		Resource:   *r,
-		HostConfig: hostConfig,
+		IsGood:        true,
+		LastUpdatedAt: time.Now(),
+		Hostname:      hostname,
+		Resource:      *r,
+		HostConfig:    hostConfig,
 	}
 	if err := SetStatusCache(hostConfig.LxdHost, s); err != nil {
 		return nil, fmt.Errorf("failed to set status to cache: %w", err)
@@ -80,11 +91,11 @@ func GetResourceFromLXD(ctx context.Context, hostConfig config.HostConfig, logge
 	cctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 	defer cancel()
 
-	host.APICallMutex.Lock()
-	defer host.APICallMutex.Unlock()
-
-	c := host.Client.WithContext(cctx)
-	defer host.Client.WithContext(context.Background())
+	c, release, err := host.Acquire(cctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to acquire lxd client: %w", err)
+	}
+	defer release()
 
 	r, hostname, err := GetResourceFromLXDWithClient(cctx, c, hostConfig.LxdHost, logger)
 	if err != nil {
@@ -95,13 +106,13 @@ func GetResourceFromLXD(ctx context.Context, hostConfig config.HostConfig, logge
 }
 
 // GetResourceFromLXDWithClient get resources from LXD API with client.
-// The caller must hold the APICallMutex and set the context on the client before calling this function.
+// The client must be bound to ctx, use LXDHost.Acquire to get it.
 func GetResourceFromLXDWithClient(ctx context.Context, client lxd.InstanceServer, host string, logger *slog.Logger) (*Resource, string, error) {
-	cpuTotal, memoryTotal, hostname, err := ScrapeLXDHostResources(client, host, logger)
+	cpuTotal, memoryTotal, hostname, err := ScrapeLXDHostResources(ctx, client, host, logger)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to scrape total resource: %w", err)
 	}
-	instances, err := GetAnyInstances(client, host)
+	instances, err := GetAnyInstances(ctx, client, host)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to retrieve list of instance: %w", err)
 	}
@@ -134,7 +145,7 @@ type LXDHostResource struct {
 }
 
 // ScrapeLXDHostResources scrape all resources
-func ScrapeLXDHostResources(client lxd.InstanceServer, host string, logger *slog.Logger) (uint64, uint64, string, error) {
+func ScrapeLXDHostResources(ctx context.Context, client lxd.InstanceServer, host string, logger *slog.Logger) (uint64, uint64, string, error) {
 	v, ok := LXDHostResourceCache.Load(host)
 	if ok {
 		r := v.(LXDHostResource)
@@ -143,7 +154,7 @@ func ScrapeLXDHostResources(client lxd.InstanceServer, host string, logger *slog
 
 	logger.Warn("failed to get host resource from cache, so scrape from lxd")
 
-	cpuTotal, memoryTotal, hostname, err := ScrapeLXDHostResourcesFromLXD(client, host)
+	cpuTotal, memoryTotal, hostname, err := ScrapeLXDHostResourcesFromLXD(ctx, client, host)
 	if err != nil {
 		return 0, 0, "", fmt.Errorf("failed to scrape total resource: %w", err)
 	}
@@ -158,17 +169,17 @@ func ScrapeLXDHostResources(client lxd.InstanceServer, host string, logger *slog
 }
 
 // ScrapeLXDHostResourcesFromLXD scrape all resources
-func ScrapeLXDHostResourcesFromLXD(client lxd.InstanceServer, host string) (uint64, uint64, string, error) {
-	startTime := time.Now()
+func ScrapeLXDHostResourcesFromLXD(ctx context.Context, client lxd.InstanceServer, host string) (uint64, uint64, string, error) {
+	done := startAPICall(ctx, host, "GetServerResources")
 	resources, err := client.GetServerResources()
-	observeAPICall(host, "GetServerResources", startTime, err)
+	done(err)
 	if err != nil {
 		return 0, 0, "", fmt.Errorf("failed to get server resource: %w", err)
 	}
 
-	startTime = time.Now()
+	done = startAPICall(ctx, host, "GetServer")
 	server, _, err := client.GetServer()
-	observeAPICall(host, "GetServer", startTime, err)
+	done(err)
 	if err != nil {
 		return 0, 0, "", fmt.Errorf("failed to get server: %w", err)
 	}
@@ -177,10 +188,10 @@ func ScrapeLXDHostResourcesFromLXD(client lxd.InstanceServer, host string) (uint
 }
 
 // GetAnyInstances get any instances from lxd
-func GetAnyInstances(client lxd.InstanceServer, host string) ([]api.Instance, error) {
-	startTime := time.Now()
+func GetAnyInstances(ctx context.Context, client lxd.InstanceServer, host string) ([]api.Instance, error) {
+	done := startAPICall(ctx, host, "GetInstances")
 	instances, err := client.GetInstances(api.InstanceTypeAny)
-	observeAPICall(host, "GetInstances", startTime, err)
+	done(err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve instances: %w", err)
 	}
@@ -1,28 +1,62 @@
 package lxdclient
 
 import (
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/lxc/lxd/shared/api"
 	"github.com/patrickmn/go-cache"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
 )
 
 // LXDStatus is status for LXD
 type LXDStatus struct {
+	// IsGood is false if the latest refresh of this host is failed
 	IsGood bool
+	// LastUpdatedAt is the time of the latest successful refresh
+	LastUpdatedAt time.Time
+	// LastError is error message of the latest refresh if IsGood is false
+	LastError string
+	// Hostname is server name of the host that is reported by LXD
+	Hostname string
 
 	Resource   Resource
 	HostConfig config.HostConfig
 }
 
+// Age return elapsed time from the latest successful refresh
+func (s LXDStatus) Age() time.Duration {
+	return time.Since(s.LastUpdatedAt)
+}
+
+// IsStale return true if status is older than threshold set by SetStatusCacheMaxAge
+func (s LXDStatus) IsStale() bool {
+	maxAge := time.Duration(statusCacheMaxAge.Load())
+	if maxAge <= 0 {
+		return false
+	}
+	return s.Age() > maxAge
+}
+
 var (
 	inmemoryCache = cache.New(10*time.Minute, cache.NoExpiration)
 
 	// ErrCacheNotFound is error message for cache not found
 	ErrCacheNotFound = fmt.Errorf("cache not found")
+
+	// statusCacheMaxAge is threshold of cache age, 0 is unlimited
+	statusCacheMaxAge atomic.Int64
 )
 
+// SetStatusCacheMaxAge set threshold of cache age.
+// A status older than maxAge is not used for allocation. 0 is unlimited.
+func SetStatusCacheMaxAge(maxAge time.Duration) {
+	statusCacheMaxAge.Store(int64(maxAge))
+}
+
 // GetCacheKey get a key of cache
 func GetCacheKey(hostname string) string {
 	return fmt.Sprintf("host-%s", hostname)
@@ -47,3 +81,39 @@ func SetStatusCache(hostname string, status LXDStatus) error {
 	inmemoryCache.Set(GetCacheKey(hostname), status, cache.DefaultExpiration)
 	return nil
 }
+
+// statusCacheUpdateMu serializes read-modify-write of a cache
+var statusCacheUpdateMu sync.Mutex
+
+// UpdateInstanceInStatusCache replace the instance in cached status of host.
+// It does nothing if the host is not cached or the instance is not found in cache.
+func UpdateInstanceInStatusCache(hostname string, instance api.Instance) error {
+	statusCacheUpdateMu.Lock()
+	defer statusCacheUpdateMu.Unlock()
+
+	status, err := GetStatusCache(hostname)
+	if errors.Is(err, ErrCacheNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get status cache: %w", err)
+	}
+
+	// copy instances, the slice in cache may be read by other goroutines
+	instances := make([]api.Instance, len(status.Resource.Instances))
+	copy(instances, status.Resource.Instances)
+	found := false
+	for i := range instances {
+		if instances[i].Name == instance.Name {
+			instances[i] = instance
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	status.Resource.Instances = instances
+	return SetStatusCache(hostname, status)
+}
@@ -0,0 +1,57 @@
+package lxdclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+)
+
+func TestLXDStatus_IsStale(t *testing.T) {
+	t.Cleanup(func() { SetStatusCacheMaxAge(0) })
+
+	fresh := LXDStatus{LastUpdatedAt: time.Now()}
+	old := LXDStatus{LastUpdatedAt: time.Now().Add(-1 * time.Minute)}
+
+	SetStatusCacheMaxAge(0)
+	if old.IsStale() {
+		t.Errorf("IsStale() = true with unlimited max age, want false")
+	}
+
+	SetStatusCacheMaxAge(30 * time.Second)
+	if fresh.IsStale() {
+		t.Errorf("fresh status IsStale() = true, want false")
+	}
+	if !old.IsStale() {
+		t.Errorf("old status IsStale() = false, want true")
+	}
+}
+
+func TestUpdateInstanceInStatusCache(t *testing.T) {
+	host := "test-update-instance"
+	original := []api.Instance{{Name: "a"}, {Name: "b"}}
+	if err := SetStatusCache(host, LXDStatus{Resource: Resource{Instances: original}}); err != nil {
+		t.Fatalf("failed to set status cache: %+v", err)
+	}
+
+	updated := api.Instance{Name: "b", InstancePut: api.InstancePut{Config: map[string]string{ConfigKeyRunnerName: "runner"}}}
+	if err := UpdateInstanceInStatusCache(host, updated); err != nil {
+		t.Fatalf("failed to update status cache: %+v", err)
+	}
+
+	got, err := GetStatusCache(host)
+	if err != nil {
+		t.Fatalf("failed to get status cache: %+v", err)
+	}
+	if got.Resource.Instances[1].Config[ConfigKeyRunnerName] != "runner" {
+		t.Errorf("instance is not updated: %+v", got.Resource.Instances[1])
+	}
+	if original[1].Config != nil {
+		t.Errorf("original slice is modified: %+v", original[1])
+	}
+
+	// not cached host is ignored
+	if err := UpdateInstanceInStatusCache("test-update-instance-not-cached", updated); err != nil {
+		t.Errorf("UpdateInstanceInStatusCache() for not cached host returned error: %+v", err)
+	}
+}
@@ -5,12 +5,39 @@ import (
 )
 
 var (
-	FailedLxdAllocate = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
+	// FailedLxdAllocateTotal counts the total number of failed allocating a pooled instance to runner
+	FailedLxdAllocateTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: "",
-			Name:      "failed_lxd_allocate",
+			Name:      "failed_lxd_allocate_total",
+			Help:      "Total number of failed allocating a pooled instance to runner by stadium and reason.",
 		},
-		[]string{"stadium", "runner_name"},
+		[]string{"stadium", "reason"},
 	)
+
+	// AllocationConflictsTotal counts the total number of conflicts with other requests that allocate the same instance
+	AllocationConflictsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "",
+			Name:      "allocation_conflicts_total",
+			Help:      "Total number of conflicts with other requests that allocate the same instance by stadium and reason.",
+		},
+		[]string{"stadium", "reason"},
+	)
+)
+
+const (
+	// AllocationConflictReserved is reason of conflict that the instance is reserved by other request
+	AllocationConflictReserved = "reserved"
+	// AllocationConflictAlreadyAllocated is reason of conflict that the instance is already allocated in LXD
+	AllocationConflictAlreadyAllocated = "already_allocated"
+)
+
+const (
+	// FailedLxdAllocateConflict is reason of failure that the instance is allocated by other request
+	FailedLxdAllocateConflict = "conflict"
+	// FailedLxdAllocateError is reason of failure that LXD API returns error
+	FailedLxdAllocateError = "error"
 )
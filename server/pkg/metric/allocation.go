@@ -0,0 +1,101 @@
+package metric
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const allocationName = "allocation"
+
+var (
+	// AllocationPhaseDuration measures the duration of each phase in AddInstance in seconds
+	AllocationPhaseDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: allocationName,
+			Name:      "phase_duration_seconds",
+			Help:      "Duration of each phase in AddInstance in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 14),
+		},
+		[]string{"phase"},
+	)
+
+	// PoolAllocationsTotal counts the total number of allocating pooled instances by result
+	PoolAllocationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: allocationName,
+			Name:      "pool_total",
+			Help:      "Total number of allocating pooled instances by image alias, flavor and result (hit, miss or resumed).",
+		},
+		[]string{"image_alias", "flavor", "result"},
+	)
+
+	// AllocationRetriesTotal counts the total number of retries for allocating pooled instances
+	AllocationRetriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: allocationName,
+			Name:      "retries_total",
+			Help:      "Total number of retries for allocating pooled instances by image alias and flavor.",
+		},
+		[]string{"image_alias", "flavor"},
+	)
+
+	// AllocationSetupFailuresTotal counts the total number of failures in setting up allocated instances
+	AllocationSetupFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: allocationName,
+			Name:      "setup_failures_total",
+			Help:      "Total number of failures in setting up allocated instances by phase and rollback (released, destroyed, quarantined or failed).",
+		},
+		[]string{"phase", "rollback"},
+	)
+)
+
+const (
+	// AllocationPhaseValidateTargetHosts is phase of validating and connecting target hosts
+	AllocationPhaseValidateTargetHosts = "validate_target_hosts"
+	// AllocationPhaseFindInstances is phase of searching pooled instances in target hosts
+	AllocationPhaseFindInstances = "find_instances"
+	// AllocationPhaseAllocate is phase of allocating pooled instance to runner, including retries
+	AllocationPhaseAllocate = "allocate"
+	// AllocationPhaseUnfreeze is phase of unfreezing the allocated instance
+	AllocationPhaseUnfreeze = "unfreeze"
+	// AllocationPhasePrepare is phase of executing preparation steps in the instance
+	AllocationPhasePrepare = "prepare"
+	// AllocationPhaseCopySetupScript is phase of copying setup script to the instance
+	AllocationPhaseCopySetupScript = "copy_setup_script"
+	// AllocationPhaseExecSetupScript is phase of executing setup script in the instance
+	AllocationPhaseExecSetupScript = "exec_setup_script"
+	// AllocationPhaseWaitRegistration is phase of waiting for the runner to be registered
+	AllocationPhaseWaitRegistration = "wait_registration"
+)
+
+const (
+	// SetupRollbackReleased is rollback that the instance is returned to the pool
+	SetupRollbackReleased = "released"
+	// SetupRollbackDestroyed is rollback that the instance is deleted
+	SetupRollbackDestroyed = "destroyed"
+	// SetupRollbackQuarantined is rollback that the instance is quarantined
+	SetupRollbackQuarantined = "quarantined"
+	// SetupRollbackFailed is rollback that is failed, the instance is left as is
+	SetupRollbackFailed = "failed"
+)
+
+const (
+	// PoolHit is result that a pooled instance is allocated
+	PoolHit = "hit"
+	// PoolMiss is result that no pooled instance can be allocated
+	PoolMiss = "miss"
+	// PoolResumed is result that the instance allocated by previous request of same runner is resumed
+	PoolResumed = "resumed"
+)
+
+// ObserveAllocationPhase records the duration of phase from startTime
+func ObserveAllocationPhase(ctx context.Context, phase string, startTime time.Time) {
+	ObserveWithExemplar(ctx, AllocationPhaseDuration.WithLabelValues(phase), time.Since(startTime).Seconds())
+}
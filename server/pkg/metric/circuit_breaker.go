@@ -0,0 +1,43 @@
+package metric
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+)
+
+const circuitBreakerName = "circuit_breaker"
+
+var (
+	circuitBreakerState = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, lxdName, "circuit_breaker_state"),
+		"State of circuit breaker for LXD host (0 for closed, 1 for open, 2 for half-open)",
+		[]string{"host"}, nil,
+	)
+)
+
+// ScraperCircuitBreaker is scraper implement for circuit breaker of LXD host
+type ScraperCircuitBreaker struct{}
+
+// Name return name
+func (ScraperCircuitBreaker) Name() string {
+	return circuitBreakerName
+}
+
+// Help return help
+func (ScraperCircuitBreaker) Help() string {
+	return "Collect from circuit breaker of LXD host"
+}
+
+// Scrape scrape metrics
+func (ScraperCircuitBreaker) Scrape(ctx context.Context, hostConfigs []config.HostConfig, ch chan<- prometheus.Metric) error {
+	for _, hc := range hostConfigs {
+		state := lxdclient.GetCircuitState(hc.LxdHost)
+		ch <- prometheus.MustNewConstMetric(
+			circuitBreakerState, prometheus.GaugeValue, float64(state), hc.LxdHost)
+	}
+	return nil
+}
@@ -90,6 +90,8 @@ type Scraper interface {
 func NewScrapers() []Scraper {
 	return []Scraper{
 		ScraperLXD{},
+		ScraperResourceCache{},
+		ScraperCircuitBreaker{},
 	}
 }
 
@@ -0,0 +1,27 @@
+package metric
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// PreDeleteHookTotal counts the total number of executing pre-delete hook by result
+	PreDeleteHookTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "delete",
+			Name:      "pre_delete_hook_total",
+			Help:      "Total number of executing pre-delete hook by result (succeeded, failed, timeout or canceled).",
+		},
+		[]string{"result"},
+	)
+)
+
+const (
+	// PreDeleteHookSucceeded is result that the hook is exited with zero
+	PreDeleteHookSucceeded = "succeeded"
+	// PreDeleteHookFailed is result that the hook is exited with non-zero or can not be executed
+	PreDeleteHookFailed = "failed"
+	// PreDeleteHookTimeout is result that the hook is not exited in timeout
+	PreDeleteHookTimeout = "timeout"
+	// PreDeleteHookCanceled is result that the request of deletion is canceled or timed out while executing the hook
+	PreDeleteHookCanceled = "canceled"
+)
@@ -54,14 +54,15 @@ func MetricsUnaryServerInterceptor() grpc.UnaryServerInterceptor {
 		method := info.FullMethod
 
 		GRPCServerRequestsTotal.WithLabelValues(method, statusCode).Inc()
-		GRPCServerRequestDuration.WithLabelValues(method, statusCode).Observe(duration)
+		ObserveWithExemplar(ctx, GRPCServerRequestDuration.WithLabelValues(method, statusCode), duration)
 
 		return resp, err
 	}
 }
 
-// LoggingUnaryServerInterceptor returns a new unary server interceptor that logs gRPC requests and responses
-func LoggingUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+// LoggingUnaryServerInterceptor returns a new unary server interceptor that logs gRPC requests and responses.
+// redact return the request for logging that secrets are removed from.
+func LoggingUnaryServerInterceptor(redact func(req any) any) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req any,
@@ -70,11 +71,12 @@ func LoggingUnaryServerInterceptor() grpc.UnaryServerInterceptor {
 	) (any, error) {
 		startTime := time.Now()
 		method := info.FullMethod
+		loggedReq := redact(req)
 
 		// Log request
 		slog.InfoContext(ctx, "gRPC request started",
 			"method", method,
-			"request", req,
+			"request", loggedReq,
 		)
 
 		// Call the handler
@@ -94,7 +96,7 @@ func LoggingUnaryServerInterceptor() grpc.UnaryServerInterceptor {
 				"status_code", statusCode.String(),
 				"error", err.Error(),
 				"error_details", st.Details(),
-				"request", req,
+				"request", loggedReq,
 				"response", resp,
 			)
 		} else {
@@ -102,7 +104,7 @@ func LoggingUnaryServerInterceptor() grpc.UnaryServerInterceptor {
 				"method", method,
 				"duration", duration,
 				"status_code", statusCode.String(),
-				"request", req,
+				"request", loggedReq,
 				"response", resp,
 			)
 		}
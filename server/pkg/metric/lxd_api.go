@@ -1,10 +1,14 @@
 package metric
 
 import (
+	"context"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/tracing"
 )
 
 var (
@@ -38,33 +42,40 @@ func init() {
 }
 
 // observeLXDAPICall records metrics for an LXD API call
-func observeLXDAPICall(host, method string, duration time.Duration, err error) {
+func observeLXDAPICall(ctx context.Context, host, method string, duration time.Duration, err error) {
 	status := "success"
 	if err != nil {
 		status = "error"
 	}
 
 	LXDAPIRequestsTotal.WithLabelValues(host, method, status).Inc()
-	LXDAPIRequestDuration.WithLabelValues(host, method, status).Observe(duration.Seconds())
+	ObserveWithExemplar(ctx, LXDAPIRequestDuration.WithLabelValues(host, method, status), duration.Seconds())
 }
 
-// LXDAPITimer is a helper to measure LXD API call duration
+// LXDAPITimer is a helper to measure LXD API call duration, and trace it as a span
 type LXDAPITimer struct {
+	ctx       context.Context
+	span      trace.Span
 	host      string
 	method    string
 	startTime time.Time
 }
 
-// NewLXDAPITimer creates a new timer for LXD API calls
-func NewLXDAPITimer(host, method string) *LXDAPITimer {
+// NewLXDAPITimer creates a new timer for LXD API calls, and starts a span as a child of ctx
+func NewLXDAPITimer(ctx context.Context, host, method string) *LXDAPITimer {
+	ctx, span := lxdclient.StartAPISpan(ctx, host, method)
 	return &LXDAPITimer{
+		ctx:       ctx,
+		span:      span,
 		host:      host,
 		method:    method,
 		startTime: time.Now(),
 	}
 }
 
-// ObserveDuration records the duration and increments the request counter
+// ObserveDuration records the duration and increments the request counter, records the result to circuit breaker, and ends the span
 func (t *LXDAPITimer) ObserveDuration(err error) {
-	observeLXDAPICall(t.host, t.method, time.Since(t.startTime), err)
+	lxdclient.RecordAPIResult(t.ctx, t.host, err)
+	observeLXDAPICall(t.ctx, t.host, t.method, time.Since(t.startTime), err)
+	tracing.End(t.span, err)
 }
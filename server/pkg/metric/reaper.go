@@ -0,0 +1,27 @@
+package metric
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// ReaperInstancesTotal counts the total number of leaked instances that are found by reaper by reason and action
+	ReaperInstancesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "reaper",
+			Name:      "instances_total",
+			Help:      "Total number of leaked instances that are found by reaper by reason and action (deleted, quarantined, dry_run or failed).",
+		},
+		[]string{"reason", "action"},
+	)
+)
+
+const (
+	// ReaperActionDeleted is action that the instance is deleted
+	ReaperActionDeleted = "deleted"
+	// ReaperActionQuarantined is action that the instance is quarantined
+	ReaperActionQuarantined = "quarantined"
+	// ReaperActionDryRun is action that the instance is only logged in dry-run mode
+	ReaperActionDryRun = "dry_run"
+	// ReaperActionFailed is action that deleting the instance is failed
+	ReaperActionFailed = "failed"
+)
@@ -0,0 +1,84 @@
+package metric
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+)
+
+const resourceCacheName = "resource_cache"
+
+var (
+	// ResourceCacheRefreshDuration measures the duration of refreshing resource cache in seconds
+	ResourceCacheRefreshDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: resourceCacheName,
+			Name:      "refresh_duration_seconds",
+			Help:      "Duration of refreshing resource cache by host in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"host"},
+	)
+
+	// ResourceCacheRefreshErrorsTotal counts the total number of failed refreshing resource cache
+	ResourceCacheRefreshErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: resourceCacheName,
+			Name:      "refresh_errors_total",
+			Help:      "Total number of failed refreshing resource cache by host.",
+		},
+		[]string{"host"},
+	)
+)
+
+var (
+	resourceCacheAge = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, resourceCacheName, "age_seconds"),
+		"Elapsed seconds from the latest successful refresh of resource cache",
+		[]string{"host"}, nil,
+	)
+	resourceCacheIsGood = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, resourceCacheName, "is_good"),
+		"Whether the latest refresh of resource cache is succeeded (1 for success, 0 for failure)",
+		[]string{"host"}, nil,
+	)
+)
+
+// ScraperResourceCache is scraper implement for resource cache
+type ScraperResourceCache struct{}
+
+// Name return name
+func (ScraperResourceCache) Name() string {
+	return resourceCacheName
+}
+
+// Help return help
+func (ScraperResourceCache) Help() string {
+	return "Collect from resource cache"
+}
+
+// Scrape scrape metrics
+func (ScraperResourceCache) Scrape(ctx context.Context, hostConfigs []config.HostConfig, ch chan<- prometheus.Metric) error {
+	for _, hc := range hostConfigs {
+		s, err := lxdclient.GetStatusCache(hc.LxdHost)
+		if err != nil {
+			// not cached yet
+			continue
+		}
+
+		isGood := 0.0
+		if s.IsGood {
+			isGood = 1.0
+		}
+		ch <- prometheus.MustNewConstMetric(
+			resourceCacheAge, prometheus.GaugeValue, s.Age().Seconds(), hc.LxdHost)
+		ch <- prometheus.MustNewConstMetric(
+			resourceCacheIsGood, prometheus.GaugeValue, isGood, hc.LxdHost)
+	}
+	return nil
+}
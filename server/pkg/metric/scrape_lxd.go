@@ -2,11 +2,8 @@ package metric
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"strconv"
-	"sync"
-	"time"
 
 	"github.com/docker/go-units"
 	"github.com/prometheus/client_golang/prometheus"
@@ -45,12 +42,13 @@ var (
 	)
 	lxdConnectErrHost = prometheus.NewDesc(
 		prometheus.BuildFQName(namespace, lxdName, "host_connect_error"),
-		"error of connect LXD host",
+		"error of the latest refresh of LXD host",
 		[]string{"hostname", "error_reason"}, nil,
 	)
 )
 
-// ScraperLXD is scraper implement for LXD
+// ScraperLXD is scraper implement for LXD.
+// It reads the resource cache that is refreshed in background, so scraping does not call LXD API.
 type ScraperLXD struct{}
 
 // Name return name
@@ -60,69 +58,38 @@ func (ScraperLXD) Name() string {
 
 // Help return help
 func (ScraperLXD) Help() string {
-	return "Collect from LXD host"
+	return "Collect from resource cache of LXD host"
 }
 
 // Scrape scrape metrics
 func (ScraperLXD) Scrape(ctx context.Context, hostConfigs []config.HostConfig, ch chan<- prometheus.Metric) error {
-	if err := scrapeLXDHosts(ctx, hostConfigs, ch); err != nil {
-		return fmt.Errorf("failed to scrape LXD host: %w", err)
+	l := slog.With("method", "ScraperLXD.Scrape")
+	for _, hc := range hostConfigs {
+		s, err := lxdclient.GetStatusCache(hc.LxdHost)
+		if err != nil {
+			// not cached yet
+			continue
+		}
+		scrapeLXDHost(hc.LxdHost, s, ch, l.With("host", hc.LxdHost))
 	}
 
 	return nil
 }
 
-func scrapeLXDHosts(ctx context.Context, hostConfigs []config.HostConfig, ch chan<- prometheus.Metric) error {
-	l := slog.With("method", "scrapeLXDHosts")
-	hosts, errHosts, err := lxdclient.ConnectLXDs(ctx, hostConfigs)
-	if err != nil {
-		return fmt.Errorf("failed to connect LXD hosts: %w", err)
+func scrapeLXDHost(host string, s lxdclient.LXDStatus, ch chan<- prometheus.Metric, logger *slog.Logger) {
+	hostname := s.Hostname
+	if hostname == "" {
+		hostname = host
 	}
 
-	for _, eh := range errHosts {
+	if !s.IsGood {
 		ch <- prometheus.MustNewConstMetric(
 			lxdConnectErrHost, prometheus.GaugeValue, 1,
-			eh.HostConfig.LxdHost, eh.Err.Error(),
+			host, s.LastError,
 		)
 	}
 
-	wg := sync.WaitGroup{}
-
-	for _, host := range hosts {
-		wg.Add(1)
-		host := host
-		go func(host *lxdclient.LXDHost) {
-			defer wg.Done()
-
-			_l := l.With("host", host.HostConfig.LxdHost)
-
-			if err := scrapeLXDHost(ctx, host, ch, _l); err != nil {
-				_l.Warn("failed to scrape LXD host", "err", err.Error())
-			}
-		}(host)
-	}
-	wg.Wait()
-	return nil
-}
-
-func scrapeLXDHost(ctx context.Context, host *lxdclient.LXDHost, ch chan<- prometheus.Metric, logger *slog.Logger) error {
-	cctx, cancel := context.WithTimeout(ctx, 20*time.Second)
-	defer cancel()
-
-	if !host.APICallMutex.TryLock() {
-		logger.Info("APICallMutex is busy, skipping scrape")
-		return nil
-	}
-	defer host.APICallMutex.Unlock()
-
-	c := host.Client.WithContext(cctx)
-	defer host.Client.WithContext(context.Background())
-
-	resources, hostname, err := lxdclient.GetResourceFromLXDWithClient(cctx, c, host.HostConfig.LxdHost, logger)
-	if err != nil {
-		return fmt.Errorf("failed to get resource from lxd: %w", err)
-	}
-
+	resources := s.Resource
 	ch <- prometheus.MustNewConstMetric(
 		lxdHostMaxCPU, prometheus.GaugeValue, float64(resources.CPUTotal), hostname)
 	ch <- prometheus.MustNewConstMetric(
@@ -144,14 +111,4 @@ func scrapeLXDHost(ctx context.Context, host *lxdclient.LXDHost, ch chan<- prome
 		lxdUsageCPU, prometheus.GaugeValue, float64(resources.CPUUsed), hostname)
 	ch <- prometheus.MustNewConstMetric(
 		lxdUsageMemory, prometheus.GaugeValue, float64(resources.MemoryUsed), hostname)
-
-	s := lxdclient.LXDStatus{
-		Resource:   *resources,
-		HostConfig: host.HostConfig,
-	}
-	if err := lxdclient.SetStatusCache(host.HostConfig.LxdHost, s); err != nil {
-		return fmt.Errorf("failed to set status cache: %w", err)
-	}
-
-	return nil
 }
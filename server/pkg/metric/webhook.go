@@ -0,0 +1,25 @@
+package metric
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// WebhookDeliveriesTotal counts the total number of webhook deliveries by event and result
+	WebhookDeliveriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "webhook",
+			Name:      "deliveries_total",
+			Help:      "Total number of webhook deliveries by event and result.",
+		},
+		[]string{"event", "result"},
+	)
+)
+
+const (
+	// WebhookResultSucceeded is result of delivery that is succeeded
+	WebhookResultSucceeded = "succeeded"
+	// WebhookResultFailed is result of delivery that is failed after all retries
+	WebhookResultFailed = "failed"
+	// WebhookResultDropped is result of delivery that is dropped because queue is full
+	WebhookResultDropped = "dropped"
+)
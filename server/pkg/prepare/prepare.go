@@ -0,0 +1,226 @@
+// Package prepare provides pipeline of steps that prepare allocated instances before executing setup script.
+package prepare
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// StepType is type of step
+type StepType string
+
+const (
+	// StepTypeExec execute command in the instance
+	StepTypeExec StepType = "exec"
+	// StepTypeFile push file in server to the instance
+	StepTypeFile StepType = "file"
+	// StepTypeTemplate render content and push it to the instance
+	StepTypeTemplate StepType = "template"
+)
+
+const (
+	defaultExecTimeout = 30 * time.Second
+	defaultFileMode    = 0644
+)
+
+// Step is a step of pipeline. Command, Path and Content are templates that are rendered with Data.
+type Step struct {
+	Name string   `json:"name"`
+	Type StepType `json:"type"`
+
+	// Command is command of exec step
+	Command []string `json:"command"`
+	// TimeoutSec is timeout of exec step. default is 30 seconds.
+	TimeoutSec uint64 `json:"timeout_sec"`
+
+	// Path is path in the instance of file and template step
+	Path string `json:"path"`
+	// Source is path in server of file step, it is not rendered
+	Source string `json:"source"`
+	// Content is content of template step
+	Content string `json:"content"`
+	// Mode is permission of file in octal (e.g. "0644")
+	Mode string `json:"mode"`
+
+	// IgnoreFailure continue the pipeline even if the step is failed
+	IgnoreFailure bool `json:"ignore_failure"`
+}
+
+// Data is values that are used in templates of steps
+type Data struct {
+	RunnerName   string
+	Host         string
+	InstanceName string
+	Labels       []string
+	ImageAlias   string
+	ResourceType string
+}
+
+// Action is rendered step
+type Action struct {
+	Type    StepType
+	Command []string
+	Timeout time.Duration
+	Path    string
+	Content []byte
+	Mode    int
+}
+
+// DefaultSteps is steps that are used if no steps are configured.
+// hostname is set by command because dbus (hostnamectl) is not response in environment of high load.
+var DefaultSteps = []Step{
+	{
+		Name:    "set hostname",
+		Type:    StepTypeExec,
+		Command: []string{"/usr/bin/hostname", "{{.RunnerName}}"},
+	},
+	{
+		Name:    "add hostname to /etc/hosts",
+		Type:    StepTypeExec,
+		Command: []string{"/bin/sh", "-c", `grep -q -- {{printf " %s$" .RunnerName | shellquote}} /etc/hosts || echo 127.0.1.1 {{shellquote .RunnerName}} >> /etc/hosts`},
+	},
+}
+
+var funcs = template.FuncMap{
+	"join":       strings.Join,
+	"shellquote": shellQuote,
+}
+
+// shellQuote quote s as a single word of POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// ParseSteps parse JSON of steps. Empty string returns DefaultSteps.
+func ParseSteps(in string) ([]Step, error) {
+	if in == "" {
+		return DefaultSteps, nil
+	}
+
+	var steps []Step
+	if err := json.Unmarshal([]byte(in), &steps); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	for idx, s := range steps {
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("invalid step %s: %w", s.DisplayName(idx), err)
+		}
+	}
+	return steps, nil
+}
+
+// DisplayName return name of step, or index if name is empty
+func (s Step) DisplayName(idx int) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return fmt.Sprintf("#%d", idx)
+}
+
+func (s Step) validate() error {
+	var templates []string
+	switch s.Type {
+	case StepTypeExec:
+		if len(s.Command) == 0 {
+			return fmt.Errorf("command is required")
+		}
+		templates = s.Command
+	case StepTypeFile:
+		if s.Path == "" || s.Source == "" {
+			return fmt.Errorf("path and source are required")
+		}
+		if _, err := os.Stat(s.Source); err != nil {
+			return fmt.Errorf("failed to stat source: %w", err)
+		}
+		templates = []string{s.Path}
+	case StepTypeTemplate:
+		if s.Path == "" {
+			return fmt.Errorf("path is required")
+		}
+		templates = []string{s.Path, s.Content}
+	default:
+		return fmt.Errorf("unknown type: %q", s.Type)
+	}
+
+	if _, err := s.mode(); err != nil {
+		return err
+	}
+	for _, t := range templates {
+		if _, err := template.New("").Funcs(funcs).Parse(t); err != nil {
+			return fmt.Errorf("failed to parse template: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s Step) mode() (int, error) {
+	if s.Mode == "" {
+		return defaultFileMode, nil
+	}
+	m, err := strconv.ParseUint(s.Mode, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse mode, need to octal: %w", err)
+	}
+	return int(m), nil
+}
+
+// Render render templates of step with d
+func (s Step) Render(d Data) (*Action, error) {
+	a := &Action{Type: s.Type}
+	switch s.Type {
+	case StepTypeExec:
+		for _, c := range s.Command {
+			r, err := render(c, d)
+			if err != nil {
+				return nil, err
+			}
+			a.Command = append(a.Command, r)
+		}
+		a.Timeout = defaultExecTimeout
+		if s.TimeoutSec > 0 {
+			a.Timeout = time.Duration(s.TimeoutSec) * time.Second
+		}
+		return a, nil
+	case StepTypeFile, StepTypeTemplate:
+		path, err := render(s.Path, d)
+		if err != nil {
+			return nil, err
+		}
+		a.Path = path
+		if a.Mode, err = s.mode(); err != nil {
+			return nil, err
+		}
+		if s.Type == StepTypeFile {
+			if a.Content, err = os.ReadFile(s.Source); err != nil {
+				return nil, fmt.Errorf("failed to read source: %w", err)
+			}
+			return a, nil
+		}
+		content, err := render(s.Content, d)
+		if err != nil {
+			return nil, err
+		}
+		a.Content = []byte(content)
+		return a, nil
+	default:
+		return nil, fmt.Errorf("unknown type: %q", s.Type)
+	}
+}
+
+func render(in string, d Data) (string, error) {
+	t, err := template.New("").Funcs(funcs).Parse(in)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, d); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return b.String(), nil
+}
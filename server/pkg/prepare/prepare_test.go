@@ -0,0 +1,116 @@
+package prepare
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseSteps(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "proxy.conf")
+	if err := os.WriteFile(source, []byte("proxy"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{name: "empty returns default", in: "", want: len(DefaultSteps)},
+		{name: "exec", in: `[{"type": "exec", "command": ["chronyc", "makestep"], "timeout_sec": 10}]`, want: 1},
+		{name: "file", in: `[{"type": "file", "path": "/etc/proxy.conf", "source": "` + source + `"}]`, want: 1},
+		{name: "template", in: `[{"type": "template", "path": "/etc/myshoes/job", "content": "{{.RunnerName}}", "mode": "0600"}]`, want: 1},
+		{name: "invalid JSON", in: `{`, wantErr: true},
+		{name: "unknown type", in: `[{"type": "unknown"}]`, wantErr: true},
+		{name: "exec without command", in: `[{"type": "exec"}]`, wantErr: true},
+		{name: "file without source", in: `[{"type": "file", "path": "/etc/proxy.conf", "source": "/not/found"}]`, wantErr: true},
+		{name: "invalid template", in: `[{"type": "template", "path": "/etc/myshoes/job", "content": "{{.RunnerName"}]`, wantErr: true},
+		{name: "invalid mode", in: `[{"type": "template", "path": "/etc/myshoes/job", "mode": "0999"}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSteps(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSteps() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("len(ParseSteps()) = %d, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestStep_Render(t *testing.T) {
+	d := Data{
+		RunnerName: "myshoes-00000000-0000-0000-0000-000000000000",
+		Host:       "https://192.0.2.1:8443",
+		Labels:     []string{"self-hosted", "linux"},
+	}
+
+	exec, err := Step{Type: StepTypeExec, Command: []string{"/usr/bin/hostname", "{{.RunnerName}}"}}.Render(d)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if want := []string{"/usr/bin/hostname", d.RunnerName}; !slices.Equal(exec.Command, want) {
+		t.Errorf("Command = %v, want %v", exec.Command, want)
+	}
+	if exec.Timeout != defaultExecTimeout {
+		t.Errorf("Timeout = %v, want %v", exec.Timeout, defaultExecTimeout)
+	}
+
+	tmpl, err := Step{Type: StepTypeTemplate, Path: "/etc/myshoes/{{.RunnerName}}", Content: `{{.Host}} {{join .Labels ","}}`, Mode: "0600", TimeoutSec: 1}.Render(d)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if want := "/etc/myshoes/" + d.RunnerName; tmpl.Path != want {
+		t.Errorf("Path = %q, want %q", tmpl.Path, want)
+	}
+	if want := "https://192.0.2.1:8443 self-hosted,linux"; string(tmpl.Content) != want {
+		t.Errorf("Content = %q, want %q", tmpl.Content, want)
+	}
+	if tmpl.Mode != 0600 {
+		t.Errorf("Mode = %o, want %o", tmpl.Mode, 0600)
+	}
+	if tmpl.Timeout != time.Duration(0) {
+		t.Errorf("Timeout of template step = %v, want 0", tmpl.Timeout)
+	}
+
+	if _, err := (Step{Type: StepTypeExec, Command: []string{"{{.Unknown}}"}}).Render(d); err == nil {
+		t.Errorf("Render() with unknown field is succeeded, want error")
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: `''`},
+		{in: "myshoes-runner", want: `'myshoes-runner'`},
+		{in: "a b;rm -rf /", want: `'a b;rm -rf /'`},
+		{in: "it's $(id)", want: `'it'"'"'s $(id)'`},
+	}
+	for _, tt := range tests {
+		if got := shellQuote(tt.in); got != tt.want {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultSteps_Quote(t *testing.T) {
+	d := Data{RunnerName: "x; touch /tmp/pwned #"}
+
+	a, err := DefaultSteps[1].Render(d)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := `grep -q -- ' x; touch /tmp/pwned #$' /etc/hosts || echo 127.0.1.1 'x; touch /tmp/pwned #' >> /etc/hosts`
+	if got := a.Command[2]; got != want {
+		t.Errorf("Command = %s, want %s", got, want)
+	}
+}
@@ -2,63 +2,152 @@ package resourcecache
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"log/slog"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
 	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/tracing"
 )
 
-// RunLXDResourceCacheTicker is run ticker for set lxd resource cache
-func RunLXDResourceCacheTicker(ctx context.Context, hcs []config.HostConfig, periodSec int64) {
-	ticker := time.NewTicker(time.Duration(periodSec) * time.Second)
-	defer ticker.Stop()
+// maxRefreshBackoff is upper limit of interval for a host that keep failing
+const maxRefreshBackoff = 5 * time.Minute
 
-	for {
-		<-ticker.C
-		if err := reloadLXDHostResourceCache(ctx, hcs); err != nil {
-			log.Fatal("failed to set lxd resource cache", "err", err.Error())
-		}
+// RunLXDResourceCacheTicker is run ticker for set lxd resource cache.
+// Each host is refreshed independently, so a failing host does not block other hosts.
+// The refreshed cache is shared with other replicas via st, and only one replica refreshes a host in a period.
+// It returns after ctx is canceled.
+func RunLXDResourceCacheTicker(ctx context.Context, hcs []config.HostConfig, periodSec int64, st store.Store) {
+	period := time.Duration(periodSec) * time.Second
+
+	var wg sync.WaitGroup
+	for _, hc := range hcs {
+		wg.Add(1)
+		go func(hc config.HostConfig) {
+			defer wg.Done()
+			runHostRefresher(ctx, hc, period, st)
+		}(hc)
 	}
+	wg.Wait()
 }
 
-func reloadLXDHostResourceCache(ctx context.Context, hcs []config.HostConfig) error {
-	l := slog.With("method", "reloadLXDHostResourceCache")
-	hosts, _, err := lxdclient.ConnectLXDs(ctx, hcs)
-	if err != nil {
-		return fmt.Errorf("failed to connect LXD hosts: %s", err)
+func runHostRefresher(ctx context.Context, hc config.HostConfig, period time.Duration, st store.Store) {
+	l := slog.With("method", "runHostRefresher", "host", hc.LxdHost)
+
+	// warm up by the cache of other replicas
+	if restoreFromSnapshot(ctx, st, hc, l) {
+		l.Info("restored lxd resource cache from snapshot")
 	}
 
-	for _, host := range hosts {
-		_l := l.With("host", host.HostConfig.LxdHost)
-		if err := setLXDHostResourceCache(ctx, host, _l); err != nil {
-			_l.Warn("failed to set lxd host resource cache", "err", err.Error())
+	timer := time.NewTimer(period)
+	defer timer.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			l.Info("stop refreshing lxd resource cache")
+			return
+		case <-timer.C:
+		}
+
+		_, leased, err := st.Reserve(ctx, refreshLeaseKey(hc.LxdHost), period)
+		if err != nil {
+			l.Warn("failed to get lease of refreshing, refresh without lease", "err", err.Error())
+			leased = true
+		}
+		if !leased {
+			// other replica is refreshing this host
+			restoreFromSnapshot(ctx, st, hc, l)
+			timer.Reset(period)
 			continue
 		}
+
+		if err := refreshLXDHostResourceCache(ctx, hc, l); err != nil {
+			if errors.Is(err, context.Canceled) {
+				continue
+			}
+			failures++
+			markLXDHostUnhealthy(hc.LxdHost, err)
+			// other replica may be able to connect the host
+			restoreFromSnapshot(ctx, st, hc, l)
+			next := nextRefreshInterval(period, failures)
+			l.Warn("failed to set lxd resource cache", "err", err.Error(), "failures", failures, "next", next.String())
+			timer.Reset(next)
+			continue
+		}
+		if err := saveSnapshot(ctx, st, hc.LxdHost); err != nil {
+			l.Warn("failed to save snapshot of lxd resource cache", "err", err.Error())
+		}
+
+		failures = 0
+		timer.Reset(period)
 	}
-	return nil
 }
 
-func setLXDHostResourceCache(ctx context.Context, host *lxdclient.LXDHost, logger *slog.Logger) error {
-	host.APICallMutex.Lock()
-	defer host.APICallMutex.Unlock()
+// nextRefreshInterval return interval of next refresh.
+// It grows exponentially by consecutive failures, and capped by maxRefreshBackoff.
+func nextRefreshInterval(period time.Duration, failures int) time.Duration {
+	next := period
+	for i := 0; i < failures; i++ {
+		next *= 2
+		if next >= maxRefreshBackoff {
+			return maxRefreshBackoff
+		}
+	}
+	return next
+}
 
-	c := host.Client.WithContext(ctx)
-	defer host.Client.WithContext(context.Background())
+func refreshLXDHostResourceCache(ctx context.Context, hc config.HostConfig, logger *slog.Logger) error {
+	ctx, span := tracing.Tracer().Start(ctx, "refreshLXDHostResourceCache", trace.WithAttributes(attribute.String("lxd.host", hc.LxdHost)))
+	startTime := time.Now()
+	err := setLXDHostResourceCache(ctx, hc, logger)
+	metric.ObserveWithExemplar(ctx, metric.ResourceCacheRefreshDuration.WithLabelValues(hc.LxdHost), time.Since(startTime).Seconds())
+	tracing.End(span, err)
+	if err != nil {
+		metric.ResourceCacheRefreshErrorsTotal.WithLabelValues(hc.LxdHost).Inc()
+		return err
+	}
+	return nil
+}
 
-	resources, _, err := lxdclient.GetResourceFromLXDWithClient(ctx, c, host.HostConfig.LxdHost, logger)
+func setLXDHostResourceCache(ctx context.Context, hc config.HostConfig, logger *slog.Logger) error {
+	resources, hostname, err := lxdclient.GetResourceFromLXD(ctx, hc, logger)
 	if err != nil {
-		return fmt.Errorf("failed to get resource from lxd: %s", err)
+		return fmt.Errorf("failed to get resource from lxd: %w", err)
 	}
 
 	s := lxdclient.LXDStatus{
-		Resource:   *resources,
-		HostConfig: host.HostConfig,
+		IsGood:        true,
+		LastUpdatedAt: time.Now(),
+		Hostname:      hostname,
+		Resource:      *resources,
+		HostConfig:    hc,
 	}
-	if err := lxdclient.SetStatusCache(host.HostConfig.LxdHost, s); err != nil {
-		return fmt.Errorf("failed to set status cache: %s", err)
+	if err := lxdclient.SetStatusCache(hc.LxdHost, s); err != nil {
+		return fmt.Errorf("failed to set status cache: %w", err)
 	}
 	return nil
 }
+
+// markLXDHostUnhealthy mark cached status as not good with the error of refresh.
+// The cached resource is kept, so allocation can use it until it is rejected by age.
+func markLXDHostUnhealthy(host string, refreshErr error) {
+	s, err := lxdclient.GetStatusCache(host)
+	if err != nil {
+		return
+	}
+	s.IsGood = false
+	s.LastError = refreshErr.Error()
+	if err := lxdclient.SetStatusCache(host, s); err != nil {
+		slog.Warn("failed to set status cache", "host", host, "err", err.Error())
+	}
+}
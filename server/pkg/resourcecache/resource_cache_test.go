@@ -0,0 +1,72 @@
+package resourcecache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+)
+
+func TestNextRefreshInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		period   time.Duration
+		failures int
+		want     time.Duration
+	}{
+		{name: "no failure", period: 10 * time.Second, failures: 0, want: 10 * time.Second},
+		{name: "first failure", period: 10 * time.Second, failures: 1, want: 20 * time.Second},
+		{name: "third failure", period: 10 * time.Second, failures: 3, want: 80 * time.Second},
+		{name: "capped", period: 10 * time.Second, failures: 10, want: maxRefreshBackoff},
+		{name: "period is longer than cap", period: 10 * time.Minute, failures: 1, want: maxRefreshBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextRefreshInterval(tt.period, tt.failures)
+			if got != tt.want {
+				t.Errorf("nextRefreshInterval(%s, %d) = %s, want %s", tt.period, tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkLXDHostUnhealthy(t *testing.T) {
+	host := "test-mark-unhealthy"
+	updatedAt := time.Now().Add(-1 * time.Minute)
+	if err := lxdclient.SetStatusCache(host, lxdclient.LXDStatus{
+		IsGood:        true,
+		LastUpdatedAt: updatedAt,
+		Resource:      lxdclient.Resource{Instances: []api.Instance{{Name: "keep"}}},
+	}); err != nil {
+		t.Fatalf("failed to set status cache: %+v", err)
+	}
+
+	markLXDHostUnhealthy(host, errors.New("connection refused"))
+
+	got, err := lxdclient.GetStatusCache(host)
+	if err != nil {
+		t.Fatalf("failed to get status cache: %+v", err)
+	}
+	if got.IsGood {
+		t.Errorf("IsGood = true, want false")
+	}
+	if got.LastError != "connection refused" {
+		t.Errorf("LastError = %q, want %q", got.LastError, "connection refused")
+	}
+	if !got.LastUpdatedAt.Equal(updatedAt) {
+		t.Errorf("LastUpdatedAt = %s, want %s (must keep the latest success)", got.LastUpdatedAt, updatedAt)
+	}
+	if len(got.Resource.Instances) != 1 {
+		t.Errorf("cached resource is not kept: %+v", got.Resource)
+	}
+
+	// not cached host must not be stored
+	markLXDHostUnhealthy("test-mark-unhealthy-not-cached", errors.New("connection refused"))
+	if _, err := lxdclient.GetStatusCache("test-mark-unhealthy-not-cached"); err == nil {
+		t.Errorf("status of not cached host is stored")
+	}
+}
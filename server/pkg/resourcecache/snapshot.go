@@ -0,0 +1,90 @@
+package resourcecache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+)
+
+// snapshotTTL is lifetime of snapshot in store, same as expiration of in-memory cache
+const snapshotTTL = 10 * time.Minute
+
+// snapshot is resource cache that is shared with other replicas.
+// HostConfig is not included because it has credentials.
+type snapshot struct {
+	IsGood        bool               `json:"is_good"`
+	LastUpdatedAt time.Time          `json:"last_updated_at"`
+	LastError     string             `json:"last_error,omitempty"`
+	Hostname      string             `json:"hostname"`
+	Resource      lxdclient.Resource `json:"resource"`
+}
+
+func refreshLeaseKey(host string) string {
+	return "refresh/" + host
+}
+
+// saveSnapshot save cached status of the host to store
+func saveSnapshot(ctx context.Context, st store.Store, host string) error {
+	s, err := lxdclient.GetStatusCache(host)
+	if err != nil {
+		return fmt.Errorf("failed to get status cache: %w", err)
+	}
+
+	b, err := json.Marshal(snapshot{
+		IsGood:        s.IsGood,
+		LastUpdatedAt: s.LastUpdatedAt,
+		LastError:     s.LastError,
+		Hostname:      s.Hostname,
+		Resource:      s.Resource,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := st.SaveSnapshot(ctx, host, b, snapshotTTL); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
+
+// restoreFromSnapshot set status cache from snapshot in store if the snapshot is newer than cached status.
+// It returns true if status cache is updated.
+func restoreFromSnapshot(ctx context.Context, st store.Store, hc config.HostConfig, l *slog.Logger) bool {
+	b, err := st.LoadSnapshot(ctx, hc.LxdHost)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			l.Warn("failed to load snapshot", "err", err.Error())
+		}
+		return false
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		l.Warn("failed to unmarshal snapshot", "err", err.Error())
+		return false
+	}
+
+	if current, err := lxdclient.GetStatusCache(hc.LxdHost); err == nil && !snap.LastUpdatedAt.After(current.LastUpdatedAt) {
+		return false
+	}
+
+	s := lxdclient.LXDStatus{
+		IsGood:        snap.IsGood,
+		LastUpdatedAt: snap.LastUpdatedAt,
+		LastError:     snap.LastError,
+		Hostname:      snap.Hostname,
+		Resource:      snap.Resource,
+		HostConfig:    hc,
+	}
+	if err := lxdclient.SetStatusCache(hc.LxdHost, s); err != nil {
+		l.Warn("failed to set status cache", "err", err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package resourcecache
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lxc/lxd/shared/api"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/config"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/store"
+)
+
+func TestRestoreFromSnapshot(t *testing.T) {
+	ctx := context.Background()
+	st := store.NewMemory()
+	hc := config.HostConfig{LxdHost: "test-restore-snapshot", LxdClientKey: "secret"}
+
+	if restoreFromSnapshot(ctx, st, hc, slog.Default()) {
+		t.Fatalf("restoreFromSnapshot() = true without snapshot, want false")
+	}
+
+	// replica A refreshed the host
+	updatedAt := time.Now().Add(-1 * time.Minute)
+	if err := lxdclient.SetStatusCache(hc.LxdHost, lxdclient.LXDStatus{
+		IsGood:        true,
+		LastUpdatedAt: updatedAt,
+		Hostname:      "node-a",
+		Resource:      lxdclient.Resource{Instances: []api.Instance{{Name: "a"}}},
+		HostConfig:    hc,
+	}); err != nil {
+		t.Fatalf("failed to set status cache: %+v", err)
+	}
+	if err := saveSnapshot(ctx, st, hc.LxdHost); err != nil {
+		t.Fatalf("failed to save snapshot: %+v", err)
+	}
+	b, err := st.LoadSnapshot(ctx, hc.LxdHost)
+	if err != nil {
+		t.Fatalf("failed to load snapshot: %+v", err)
+	}
+	if got := string(b); strings.Contains(got, "secret") {
+		t.Errorf("snapshot has credential of host: %s", got)
+	}
+
+	// same status is not restored
+	if restoreFromSnapshot(ctx, st, hc, slog.Default()) {
+		t.Errorf("restoreFromSnapshot() = true for snapshot that is not newer, want false")
+	}
+
+	// replica B has older cache
+	if err := lxdclient.SetStatusCache(hc.LxdHost, lxdclient.LXDStatus{
+		LastUpdatedAt: updatedAt.Add(-1 * time.Minute),
+		HostConfig:    hc,
+	}); err != nil {
+		t.Fatalf("failed to set status cache: %+v", err)
+	}
+	if !restoreFromSnapshot(ctx, st, hc, slog.Default()) {
+		t.Fatalf("restoreFromSnapshot() = false for newer snapshot, want true")
+	}
+	got, err := lxdclient.GetStatusCache(hc.LxdHost)
+	if err != nil {
+		t.Fatalf("failed to get status cache: %+v", err)
+	}
+	if !got.IsGood || !got.LastUpdatedAt.Equal(updatedAt) || got.Hostname != "node-a" || len(got.Resource.Instances) != 1 {
+		t.Errorf("status cache is not restored: %+v", got)
+	}
+	if got.HostConfig.LxdClientKey != "secret" {
+		t.Errorf("HostConfig is not set from local config")
+	}
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is Store implementation in process memory.
+// It is used when server is run as a single replica.
+type Memory struct {
+	mu sync.Mutex
+
+	// reservations is map of reservation key and its token
+	reservations map[string]memoryReservation
+	cordoned     map[string]struct{}
+	snapshots    map[string]memorySnapshot
+	tombstones   map[string]memoryTombstone
+}
+
+type memoryReservation struct {
+	token     string
+	expiredAt time.Time
+}
+
+type memoryTombstone struct {
+	value     string
+	expiredAt time.Time
+}
+
+type memorySnapshot struct {
+	data      []byte
+	expiredAt time.Time
+}
+
+// NewMemory create Memory
+func NewMemory() *Memory {
+	return &Memory{
+		reservations: make(map[string]memoryReservation),
+		cordoned:     make(map[string]struct{}),
+		snapshots:    make(map[string]memorySnapshot),
+		tombstones:   make(map[string]memoryTombstone),
+	}
+}
+
+// Reserve reserve key until ttl is passed
+func (m *Memory) Reserve(_ context.Context, key string, ttl time.Duration) (string, bool, error) {
+	token, err := newReservationToken()
+	if err != nil {
+		return "", false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r, ok := m.reservations[key]; ok && time.Now().Before(r.expiredAt) {
+		return "", false, nil
+	}
+	m.reservations[key] = memoryReservation{token: token, expiredAt: time.Now().Add(ttl)}
+	return token, true, nil
+}
+
+// Release release the reservation of key that has token
+func (m *Memory) Release(_ context.Context, key, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r, ok := m.reservations[key]; ok && r.token == token {
+		delete(m.reservations, key)
+	}
+	return nil
+}
+
+// SetCordon set cordon state of the host
+func (m *Memory) SetCordon(_ context.Context, host string, cordoned bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cordoned {
+		m.cordoned[host] = struct{}{}
+	} else {
+		delete(m.cordoned, host)
+	}
+	return nil
+}
+
+// IsCordoned return whether the host is cordoned
+func (m *Memory) IsCordoned(_ context.Context, host string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.cordoned[host]
+	return ok, nil
+}
+
+// SaveSnapshot save the snapshot of resource cache
+func (m *Memory) SaveSnapshot(_ context.Context, host string, snapshot []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.snapshots[host] = memorySnapshot{
+		data:      snapshot,
+		expiredAt: time.Now().Add(ttl),
+	}
+	return nil
+}
+
+// LoadSnapshot load the snapshot of resource cache
+func (m *Memory) LoadSnapshot(_ context.Context, host string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.snapshots[host]
+	if !ok || time.Now().After(s.expiredAt) {
+		return nil, ErrNotFound
+	}
+	return s.data, nil
+}
+
+// SaveTombstone record that the object of key is deleted
+func (m *Memory) SaveTombstone(_ context.Context, key, value string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for k, t := range m.tombstones {
+		if now.After(t.expiredAt) {
+			delete(m.tombstones, k)
+		}
+	}
+	m.tombstones[key] = memoryTombstone{
+		value:     value,
+		expiredAt: now.Add(ttl),
+	}
+	return nil
+}
+
+// LoadTombstone load value of the tombstone of key
+func (m *Memory) LoadTombstone(_ context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.tombstones[key]
+	if !ok || time.Now().After(t.expiredAt) {
+		return "", ErrNotFound
+	}
+	return t.value, nil
+}
+
+// Close do nothing
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisKeyPrefix            = "shoes-lxd-multi:"
+	redisKeyReservationPrefix = redisKeyPrefix + "reservation:"
+	redisKeyCordonedHosts     = redisKeyPrefix + "cordoned_hosts"
+	redisKeySnapshotPrefix    = redisKeyPrefix + "snapshot:"
+	redisKeyTombstonePrefix   = redisKeyPrefix + "tombstone:"
+)
+
+// releaseScript delete the reservation only if it has the token of the caller
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Redis is Store implementation by Redis (or Redis compatible server).
+// It is used when server is run as multiple replicas.
+type Redis struct {
+	client *redis.Client
+
+	// owner is identifier of this replica, that is prefix of reservation token for debugging
+	owner string
+}
+
+// NewRedis create Redis and check connection
+func NewRedis(ctx context.Context, addr, password string, db int) (*Redis, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	owner, err := newOwnerID()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to generate owner id: %w", err)
+	}
+
+	return &Redis{
+		client: client,
+		owner:  owner,
+	}, nil
+}
+
+func newOwnerID() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("failed to get hostname: %w", err)
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to read random: %w", err)
+	}
+	return fmt.Sprintf("%s-%s", hostname, hex.EncodeToString(b)), nil
+}
+
+// Reserve reserve key until ttl is passed
+func (r *Redis) Reserve(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	token, err := newReservationToken()
+	if err != nil {
+		return "", false, err
+	}
+	token = r.owner + "/" + token
+	ok, err := r.client.SetNX(ctx, redisKeyReservationPrefix+key, token, ttl).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to set reservation: %w", err)
+	}
+	if !ok {
+		return "", false, nil
+	}
+	return token, true, nil
+}
+
+// Release release the reservation of key that has token
+func (r *Redis) Release(ctx context.Context, key, token string) error {
+	if err := releaseScript.Run(ctx, r.client, []string{redisKeyReservationPrefix + key}, token).Err(); err != nil {
+		return fmt.Errorf("failed to delete reservation: %w", err)
+	}
+	return nil
+}
+
+// SetCordon set cordon state of the host
+func (r *Redis) SetCordon(ctx context.Context, host string, cordoned bool) error {
+	var err error
+	if cordoned {
+		err = r.client.SAdd(ctx, redisKeyCordonedHosts, host).Err()
+	} else {
+		err = r.client.SRem(ctx, redisKeyCordonedHosts, host).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update cordoned hosts: %w", err)
+	}
+	return nil
+}
+
+// IsCordoned return whether the host is cordoned
+func (r *Redis) IsCordoned(ctx context.Context, host string) (bool, error) {
+	ok, err := r.client.SIsMember(ctx, redisKeyCordonedHosts, host).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to get cordoned hosts: %w", err)
+	}
+	return ok, nil
+}
+
+// SaveSnapshot save the snapshot of resource cache
+func (r *Redis) SaveSnapshot(ctx context.Context, host string, snapshot []byte, ttl time.Duration) error {
+	if err := r.client.Set(ctx, redisKeySnapshotPrefix+host, snapshot, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot load the snapshot of resource cache
+func (r *Redis) LoadSnapshot(ctx context.Context, host string) ([]byte, error) {
+	b, err := r.client.Get(ctx, redisKeySnapshotPrefix+host).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	return b, nil
+}
+
+// SaveTombstone record that the object of key is deleted
+func (r *Redis) SaveTombstone(ctx context.Context, key, value string, ttl time.Duration) error {
+	if err := r.client.Set(ctx, redisKeyTombstonePrefix+key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set tombstone: %w", err)
+	}
+	return nil
+}
+
+// LoadTombstone load value of the tombstone of key
+func (r *Redis) LoadTombstone(ctx context.Context, key string) (string, error) {
+	v, err := r.client.Get(ctx, redisKeyTombstonePrefix+key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get tombstone: %w", err)
+	}
+	return v, nil
+}
+
+// Close close connection to redis
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,60 @@
+// Package store provides state that is shared between replicas of server.
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNotFound is error for key is not found in store
+	ErrNotFound = errors.New("not found in store")
+)
+
+// Store is storage of state that is shared between replicas
+type Store interface {
+	// Reserve reserve key until ttl is passed, and return token of the reservation.
+	// It returns false if the key is already reserved by other request.
+	Reserve(ctx context.Context, key string, ttl time.Duration) (string, bool, error)
+	// Release release the reservation of key that has token.
+	// The reservation that is taken over by other request after ttl is kept.
+	Release(ctx context.Context, key, token string) error
+
+	// SetCordon set cordon state of the host. Cordoned host is not used for new allocation.
+	SetCordon(ctx context.Context, host string, cordoned bool) error
+	// IsCordoned return whether the host is cordoned
+	IsCordoned(ctx context.Context, host string) (bool, error)
+
+	// SaveSnapshot save the snapshot of resource cache for the host until ttl is passed
+	SaveSnapshot(ctx context.Context, host string, snapshot []byte, ttl time.Duration) error
+	// LoadSnapshot load the snapshot of resource cache for the host.
+	// It returns ErrNotFound if the snapshot is not saved or expired.
+	LoadSnapshot(ctx context.Context, host string) ([]byte, error)
+
+	// SaveTombstone record that the object of key is deleted until ttl is passed. value is stored with it.
+	SaveTombstone(ctx context.Context, key, value string, ttl time.Duration) error
+	// LoadTombstone load value of the tombstone of key.
+	// It returns ErrNotFound if the tombstone is not saved or expired.
+	LoadTombstone(ctx context.Context, key string) (string, error)
+
+	// Close close connection to store
+	Close() error
+}
+
+// newReservationToken return random token that identifies a reservation
+func newReservationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to read random: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ReservationKey return key of reservation for the instance
+func ReservationKey(host, instanceName string) string {
+	return host + "/" + instanceName
+}
@@ -0,0 +1,249 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRedis(t *testing.T, mr *miniredis.Miniredis) *Redis {
+	t.Helper()
+
+	r, err := NewRedis(context.Background(), mr.Addr(), "", 0)
+	if err != nil {
+		t.Fatalf("failed to create redis store: %+v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func testStores(t *testing.T) map[string]Store {
+	t.Helper()
+
+	return map[string]Store{
+		"memory": NewMemory(),
+		"redis":  newTestRedis(t, miniredis.RunT(t)),
+	}
+}
+
+func TestStore_Reserve(t *testing.T) {
+	ctx := context.Background()
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			key := ReservationKey("host-a", "instance-1")
+
+			token, ok, err := s.Reserve(ctx, key, time.Minute)
+			if err != nil || !ok || token == "" {
+				t.Fatalf("Reserve() = %q, %v, %v for new instance, want true", token, ok, err)
+			}
+			if _, ok, err := s.Reserve(ctx, key, time.Minute); err != nil || ok {
+				t.Fatalf("Reserve() = %v, %v for reserved instance, want false", ok, err)
+			}
+			if _, ok, err := s.Reserve(ctx, ReservationKey("host-b", "instance-1"), time.Minute); err != nil || !ok {
+				t.Fatalf("Reserve() = %v, %v for same name in other host, want true", ok, err)
+			}
+
+			// release by other token must not release the reservation
+			if err := s.Release(ctx, key, "other-token"); err != nil {
+				t.Fatalf("failed to release: %+v", err)
+			}
+			if _, ok, err := s.Reserve(ctx, key, time.Minute); err != nil || ok {
+				t.Fatalf("Reserve() = %v, %v after release by other token, want false", ok, err)
+			}
+
+			if err := s.Release(ctx, key, token); err != nil {
+				t.Fatalf("failed to release: %+v", err)
+			}
+			if _, ok, err := s.Reserve(ctx, key, time.Minute); err != nil || !ok {
+				t.Fatalf("Reserve() = %v, %v after release, want true", ok, err)
+			}
+		})
+	}
+}
+
+func TestStore_Reserve_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			const goroutines = 50
+			var wg sync.WaitGroup
+			var reserved atomic.Int32
+			for range goroutines {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, ok, err := s.Reserve(ctx, ReservationKey("host-a", "instance-1"), time.Minute)
+					if err != nil {
+						t.Errorf("failed to reserve: %+v", err)
+						return
+					}
+					if ok {
+						reserved.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if got := reserved.Load(); got != 1 {
+				t.Errorf("instance is reserved by %d requests, want 1", got)
+			}
+		})
+	}
+}
+
+func TestMemory_Reserve_Expired(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemory()
+
+	tokenA, ok, _ := s.Reserve(ctx, "key", time.Millisecond)
+	if !ok {
+		t.Fatalf("Reserve() = false for new key, want true")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := s.Reserve(ctx, "key", time.Minute); !ok {
+		t.Fatalf("Reserve() = false for expired reservation, want true")
+	}
+	// late release of expired reservation must not release the reservation of other request
+	if err := s.Release(ctx, "key", tokenA); err != nil {
+		t.Fatalf("failed to release: %+v", err)
+	}
+	if _, ok, _ := s.Reserve(ctx, "key", time.Minute); ok {
+		t.Fatalf("Reserve() = true after late release, want false")
+	}
+}
+
+func TestRedis_Replicas(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	replicaA := newTestRedis(t, mr)
+	replicaB := newTestRedis(t, mr)
+
+	key := ReservationKey("host-a", "instance-1")
+	tokenA, ok, err := replicaA.Reserve(ctx, key, time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("Reserve() by replica A = %v, %v, want true", ok, err)
+	}
+	if _, ok, err := replicaB.Reserve(ctx, key, time.Minute); err != nil || ok {
+		t.Fatalf("Reserve() by replica B = %v, %v, want false", ok, err)
+	}
+
+	// reservation of replica A is expired and taken over by replica B
+	mr.FastForward(2 * time.Minute)
+	if _, ok, err := replicaB.Reserve(ctx, key, time.Minute); err != nil || !ok {
+		t.Fatalf("Reserve() by replica B after expiration = %v, %v, want true", ok, err)
+	}
+	// late release by replica A must not release the reservation of replica B
+	if err := replicaA.Release(ctx, key, tokenA); err != nil {
+		t.Fatalf("failed to release: %+v", err)
+	}
+	if _, ok, err := replicaA.Reserve(ctx, key, time.Minute); err != nil || ok {
+		t.Fatalf("Reserve() by replica A = %v, %v, want false (released reservation of other replica)", ok, err)
+	}
+
+	// late release by other request in same replica must not release the reservation
+	key = ReservationKey("host-a", "instance-2")
+	tokenA, _, _ = replicaA.Reserve(ctx, key, time.Minute)
+	mr.FastForward(2 * time.Minute)
+	if _, ok, err := replicaA.Reserve(ctx, key, time.Minute); err != nil || !ok {
+		t.Fatalf("Reserve() by other request after expiration = %v, %v, want true", ok, err)
+	}
+	if err := replicaA.Release(ctx, key, tokenA); err != nil {
+		t.Fatalf("failed to release: %+v", err)
+	}
+	if _, ok, err := replicaB.Reserve(ctx, key, time.Minute); err != nil || ok {
+		t.Fatalf("Reserve() = %v, %v, want false (released reservation of other request)", ok, err)
+	}
+
+	// tombstone is shared, and expired after ttl
+	if err := replicaA.SaveTombstone(ctx, "instance-1", "runner-1", time.Minute); err != nil {
+		t.Fatalf("failed to save tombstone: %+v", err)
+	}
+	if v, err := replicaB.LoadTombstone(ctx, "instance-1"); err != nil || v != "runner-1" {
+		t.Fatalf("LoadTombstone() by replica B = %q, %v, want runner-1", v, err)
+	}
+	mr.FastForward(2 * time.Minute)
+	if _, err := replicaB.LoadTombstone(ctx, "instance-1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("LoadTombstone() by replica B after expiration error = %v, want ErrNotFound", err)
+	}
+
+	// cordon is shared
+	if err := replicaA.SetCordon(ctx, "host-a", true); err != nil {
+		t.Fatalf("failed to cordon: %+v", err)
+	}
+	if cordoned, err := replicaB.IsCordoned(ctx, "host-a"); err != nil || !cordoned {
+		t.Fatalf("IsCordoned() by replica B = %v, %v, want true", cordoned, err)
+	}
+}
+
+func TestStore_Cordon(t *testing.T) {
+	ctx := context.Background()
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if cordoned, err := s.IsCordoned(ctx, "host-a"); err != nil || cordoned {
+				t.Fatalf("IsCordoned() = %v, %v for new host, want false", cordoned, err)
+			}
+			if err := s.SetCordon(ctx, "host-a", true); err != nil {
+				t.Fatalf("failed to cordon: %+v", err)
+			}
+			if cordoned, err := s.IsCordoned(ctx, "host-a"); err != nil || !cordoned {
+				t.Fatalf("IsCordoned() = %v, %v after cordon, want true", cordoned, err)
+			}
+			if cordoned, err := s.IsCordoned(ctx, "host-b"); err != nil || cordoned {
+				t.Fatalf("IsCordoned() = %v, %v for other host, want false", cordoned, err)
+			}
+			if err := s.SetCordon(ctx, "host-a", false); err != nil {
+				t.Fatalf("failed to uncordon: %+v", err)
+			}
+			if cordoned, err := s.IsCordoned(ctx, "host-a"); err != nil || cordoned {
+				t.Fatalf("IsCordoned() = %v, %v after uncordon, want false", cordoned, err)
+			}
+		})
+	}
+}
+
+func TestStore_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.LoadSnapshot(ctx, "host-a"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("LoadSnapshot() error = %v for not saved host, want ErrNotFound", err)
+			}
+			if err := s.SaveSnapshot(ctx, "host-a", []byte("snapshot"), time.Minute); err != nil {
+				t.Fatalf("failed to save snapshot: %+v", err)
+			}
+			got, err := s.LoadSnapshot(ctx, "host-a")
+			if err != nil {
+				t.Fatalf("failed to load snapshot: %+v", err)
+			}
+			if string(got) != "snapshot" {
+				t.Errorf("LoadSnapshot() = %q, want %q", got, "snapshot")
+			}
+		})
+	}
+}
+
+func TestStore_Tombstone(t *testing.T) {
+	ctx := context.Background()
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.LoadTombstone(ctx, "instance-1"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("LoadTombstone() error = %v for not deleted key, want ErrNotFound", err)
+			}
+			if err := s.SaveTombstone(ctx, "instance-1", "runner-1", time.Minute); err != nil {
+				t.Fatalf("failed to save tombstone: %+v", err)
+			}
+			got, err := s.LoadTombstone(ctx, "instance-1")
+			if err != nil {
+				t.Fatalf("failed to load tombstone: %+v", err)
+			}
+			if got != "runner-1" {
+				t.Errorf("LoadTombstone() = %q, want %q", got, "runner-1")
+			}
+		})
+	}
+}
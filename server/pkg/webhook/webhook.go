@@ -0,0 +1,307 @@
+// Package webhook provides notification of lifecycle events to webhook sinks.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/lxdclient"
+	"github.com/whywaita/shoes-lxd-multi/server/pkg/metric"
+)
+
+// EventType is type of lifecycle event
+type EventType string
+
+const (
+	// EventAllocationSucceeded is sent when AddInstance is succeeded
+	EventAllocationSucceeded EventType = "allocation_succeeded"
+	// EventAllocationFailed is sent when AddInstance is failed
+	EventAllocationFailed EventType = "allocation_failed"
+	// EventPoolExhausted is sent when no pooled instance is available for the image and flavor
+	EventPoolExhausted EventType = "pool_exhausted"
+	// EventHostUnreachable is sent when circuit breaker of the host is opened
+	EventHostUnreachable EventType = "host_unreachable"
+	// EventSetupScriptFailed is sent when setup script is exited with non-zero
+	EventSetupScriptFailed EventType = "setup_script_failed"
+	// EventRunnerRegistrationFailed is sent when the runner is not registered after setup script is started
+	EventRunnerRegistrationFailed EventType = "runner_registration_failed"
+	// EventInstanceQuarantined is sent when the instance is quarantined instead of deleting
+	EventInstanceQuarantined EventType = "instance_quarantined"
+	// EventInstanceDeleted is sent when DeleteInstance is succeeded
+	EventInstanceDeleted EventType = "instance_deleted"
+)
+
+// Event is payload of webhook
+type Event struct {
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+
+	RunnerName   string `json:"runner_name,omitempty"`
+	Host         string `json:"host,omitempty"`
+	InstanceName string `json:"instance_name,omitempty"`
+	ImageAlias   string `json:"image_alias,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+	Message      string `json:"message,omitempty"`
+}
+
+const (
+	// HeaderEvent is header of event type
+	HeaderEvent = "X-Shoes-LXD-Multi-Event"
+	// HeaderTimestamp is header of unix time that the payload is signed
+	HeaderTimestamp = "X-Shoes-LXD-Multi-Timestamp"
+	// HeaderSignature is header of signature, "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+	HeaderSignature = "X-Shoes-LXD-Multi-Signature"
+)
+
+// Sign return signature of payload
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sink is destination of webhook
+type Sink struct {
+	URL string `json:"url"`
+	// Secret is key of signature, the payload is not signed if empty
+	Secret string `json:"secret"`
+	// Events is list of event types to send, all events are sent if empty
+	Events []EventType `json:"events"`
+	// MaxRetries is number of retries after first attempt
+	MaxRetries *int `json:"max_retries"`
+	// TimeoutSec is timeout of a request in seconds
+	TimeoutSec int `json:"timeout_sec"`
+}
+
+var eventTypes = map[EventType]struct{}{
+	EventAllocationSucceeded:      {},
+	EventAllocationFailed:         {},
+	EventPoolExhausted:            {},
+	EventHostUnreachable:          {},
+	EventSetupScriptFailed:        {},
+	EventRunnerRegistrationFailed: {},
+	EventInstanceQuarantined:      {},
+	EventInstanceDeleted:          {},
+}
+
+// ParseSinks parse JSON of sinks. Empty string returns no sinks.
+func ParseSinks(in string) ([]Sink, error) {
+	if in == "" {
+		return nil, nil
+	}
+
+	var sinks []Sink
+	if err := json.Unmarshal([]byte(in), &sinks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	for _, s := range sinks {
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("invalid url of webhook: %q", s.URL)
+		}
+		for _, e := range s.Events {
+			if _, ok := eventTypes[e]; !ok {
+				return nil, fmt.Errorf("unknown event type: %q", e)
+			}
+		}
+	}
+	return sinks, nil
+}
+
+const (
+	defaultMaxRetries = 3
+	defaultTimeout    = 5 * time.Second
+	queueSize         = 1000
+)
+
+func (s Sink) accept(t EventType) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Sink) maxRetries() int {
+	if s.MaxRetries == nil {
+		return defaultMaxRetries
+	}
+	return *s.MaxRetries
+}
+
+func (s Sink) timeout() time.Duration {
+	if s.TimeoutSec <= 0 {
+		return defaultTimeout
+	}
+	return time.Duration(s.TimeoutSec) * time.Second
+}
+
+type delivery struct {
+	sink  Sink
+	event Event
+}
+
+// Dispatcher send events to sinks asynchronously
+type Dispatcher struct {
+	sinks  []Sink
+	client *http.Client
+	queue  chan delivery
+
+	// retryBackoff is base interval of retry, doubled by each retry
+	retryBackoff time.Duration
+}
+
+// NewDispatcher create Dispatcher
+func NewDispatcher(sinks []Sink) *Dispatcher {
+	return &Dispatcher{
+		sinks:        sinks,
+		client:       &http.Client{},
+		queue:        make(chan delivery, queueSize),
+		retryBackoff: 1 * time.Second,
+	}
+}
+
+// Run send queued events until ctx is canceled
+func (d *Dispatcher) Run(ctx context.Context, workers int) {
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case dl := <-d.queue:
+					d.deliver(ctx, dl)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Emit queue the event to sinks that accept it. It does not block, the event is dropped if queue is full.
+func (d *Dispatcher) Emit(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	for _, s := range d.sinks {
+		if !s.accept(e.Type) {
+			continue
+		}
+		select {
+		case d.queue <- delivery{sink: s, event: e}:
+		default:
+			slog.Warn("webhook queue is full, drop event", "type", e.Type, "url", s.URL)
+			metric.WebhookDeliveriesTotal.WithLabelValues(string(e.Type), metric.WebhookResultDropped).Inc()
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, dl delivery) {
+	l := slog.With("method", "deliver", "type", dl.event.Type, "url", dl.sink.URL)
+
+	body, err := json.Marshal(dl.event)
+	if err != nil {
+		l.Warn("failed to marshal event", "err", err.Error())
+		return
+	}
+
+	backoff := d.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := d.post(ctx, dl.sink, dl.event.Type, body)
+		if err == nil {
+			metric.WebhookDeliveriesTotal.WithLabelValues(string(dl.event.Type), metric.WebhookResultSucceeded).Inc()
+			return
+		}
+		if attempt >= dl.sink.maxRetries() {
+			l.Warn("failed to send webhook, give up", "err", err.Error(), "attempts", attempt+1)
+			metric.WebhookDeliveriesTotal.WithLabelValues(string(dl.event.Type), metric.WebhookResultFailed).Inc()
+			return
+		}
+		l.Info("failed to send webhook, will retry", "err", err.Error(), "attempts", attempt+1)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (d *Dispatcher) post(ctx context.Context, sink Sink, t EventType, body []byte) error {
+	cctx, cancel := context.WithTimeout(ctx, sink.timeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(cctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(t))
+	if sink.Secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(HeaderSignature, Sign(sink.Secret, timestamp, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+var defaultDispatcher atomic.Pointer[Dispatcher]
+
+// SetDefault set dispatcher that is used by Emit
+func SetDefault(d *Dispatcher) {
+	defaultDispatcher.Store(d)
+}
+
+// Emit queue the event to default dispatcher. It does nothing if webhook is not configured.
+func Emit(e Event) {
+	if d := defaultDispatcher.Load(); d != nil {
+		d.Emit(e)
+	}
+}
+
+func init() {
+	lxdclient.SetCircuitStateObserver(func(host string, state lxdclient.CircuitState) {
+		if state != lxdclient.CircuitOpen {
+			return
+		}
+		Emit(Event{
+			Type:    EventHostUnreachable,
+			Host:    host,
+			Message: "circuit breaker is opened",
+		})
+	})
+}
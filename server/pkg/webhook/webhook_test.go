@@ -0,0 +1,164 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseSinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{name: "empty", in: "", want: 0},
+		{name: "valid", in: `[{"url": "https://example.com/hook", "events": ["allocation_failed"]}, {"url": "http://example.com"}]`, want: 2},
+		{name: "instance quarantined", in: `[{"url": "https://example.com", "events": ["instance_quarantined"]}]`, want: 1},
+		{name: "invalid json", in: `{`, wantErr: true},
+		{name: "invalid url", in: `[{"url": "example.com"}]`, wantErr: true},
+		{name: "unknown event", in: `[{"url": "https://example.com", "events": ["unknown"]}]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSinks(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSinks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("ParseSinks() returned %d sinks, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+// TestParseSinks_AllEventTypes check that all EventType constants defined in this package are accepted
+func TestParseSinks_AllEventTypes(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "webhook.go", nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse webhook.go: %+v", err)
+	}
+
+	var names []string
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if ident, ok := vs.Type.(*ast.Ident); ok && ident.Name == "EventType" {
+				for i, name := range vs.Names {
+					lit := vs.Values[i].(*ast.BasicLit)
+					v, err := strconv.Unquote(lit.Value)
+					if err != nil {
+						t.Fatalf("failed to unquote value of %s: %+v", name.Name, err)
+					}
+					names = append(names, v)
+				}
+			}
+		}
+	}
+	if len(names) == 0 {
+		t.Fatal("no EventType constants are found")
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			in := fmt.Sprintf(`[{"url": "https://example.com", "events": [%q]}]`, name)
+			if _, err := ParseSinks(in); err != nil {
+				t.Errorf("ParseSinks() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDispatcher_SignAndRetry(t *testing.T) {
+	const secret = "secret"
+	var attempts atomic.Int32
+	received := make(chan Event, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// first attempt is failed
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if err != nil {
+			t.Errorf("invalid timestamp header: %+v", err)
+		}
+		if got, want := r.Header.Get(HeaderSignature), Sign(secret, timestamp, body); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		if got := r.Header.Get(HeaderEvent); got != string(EventAllocationFailed) {
+			t.Errorf("event header = %q, want %q", got, EventAllocationFailed)
+		}
+
+		var e Event
+		if err := json.Unmarshal(body, &e); err != nil {
+			t.Errorf("failed to unmarshal body: %+v", err)
+		}
+		received <- e
+	}))
+	t.Cleanup(srv.Close)
+
+	d := NewDispatcher([]Sink{
+		{URL: srv.URL, Secret: secret, Events: []EventType{EventAllocationFailed}},
+	})
+	d.retryBackoff = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go d.Run(ctx, 1)
+
+	// not subscribed event is not sent
+	d.Emit(Event{Type: EventAllocationSucceeded, RunnerName: "runner-0"})
+	d.Emit(Event{Type: EventAllocationFailed, RunnerName: "runner-1"})
+
+	select {
+	case e := <-received:
+		if e.RunnerName != "runner-1" || e.Time.IsZero() {
+			t.Errorf("received event = %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook is not received")
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("webhook is sent %d times, want 2", got)
+	}
+}
+
+func TestDispatcher_GiveUp(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+
+	maxRetries := 2
+	d := NewDispatcher(nil)
+	d.retryBackoff = time.Millisecond
+	d.deliver(context.Background(), delivery{
+		sink:  Sink{URL: srv.URL, MaxRetries: &maxRetries},
+		event: Event{Type: EventInstanceDeleted},
+	})
+
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("webhook is sent %d times, want 3 (first attempt and 2 retries)", got)
+	}
+}
@@ -7,6 +7,16 @@ import (
 	"log"
 	"os"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
@@ -34,6 +44,12 @@ func main() {
 }
 
 func run() error {
+	shutdownTracing, err := setupTracing(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	handshake := plugin.HandshakeConfig{
 		ProtocolVersion:  1,
 		MagicCookieKey:   "SHOES_PLUGIN_MAGIC_COOKIE",
@@ -52,6 +68,44 @@ func run() error {
 	return nil
 }
 
+const instrumentationName = "github.com/whywaita/shoes-lxd-multi/shoes-lxd-multi"
+
+// setupTracing set global tracer provider that exports spans by OTLP gRPC.
+// The exporter is configured by standard environment values (e.g. OTEL_EXPORTER_OTLP_ENDPOINT).
+// Trace context is propagated to server even if OTLP endpoint is not configured.
+func setupTracing(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName("shoes-lxd-multi")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// endSpan record err to span and end it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
 // LXDMultiPlugin is plugin for lxd multi node
 type LXDMultiPlugin struct {
 	plugin.Plugin
@@ -84,6 +138,8 @@ func (l *LXDMultiPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) e
 	grpcConn, err := grpc.Dial(
 		serverEndpoint,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		// propagate trace context to server
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 		//lint:ignore SA1019 Dial is marked as deprecated but support is continued ref: https://github.com/artefactual-sdps/enduro/pull/1011#issuecomment-2043632214
 		grpc.WithBlock(),
 	)
@@ -125,7 +181,10 @@ func NewClient(targetHosts []string, conn *grpc.ClientConn, osVersion string) *C
 }
 
 // AddInstance add a lxd instance.
-func (l Client) AddInstance(ctx context.Context, req *pb.AddInstanceRequest) (*pb.AddInstanceResponse, error) {
+func (l Client) AddInstance(ctx context.Context, req *pb.AddInstanceRequest) (_ *pb.AddInstanceResponse, err error) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, "AddInstance", trace.WithAttributes(attribute.String("runner.name", req.RunnerName)))
+	defer func() { endSpan(span, err) }()
+
 	slClient := shoeslxdpb.NewShoesLXDMultiClient(l.conn)
 	slReq := &shoeslxdpb.AddInstanceRequest{
 		RunnerName:   req.RunnerName,
@@ -148,7 +207,10 @@ func (l Client) AddInstance(ctx context.Context, req *pb.AddInstanceRequest) (*p
 }
 
 // DeleteInstance delete a lxd instance.
-func (l Client) DeleteInstance(ctx context.Context, req *pb.DeleteInstanceRequest) (*pb.DeleteInstanceResponse, error) {
+func (l Client) DeleteInstance(ctx context.Context, req *pb.DeleteInstanceRequest) (_ *pb.DeleteInstanceResponse, err error) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, "DeleteInstance", trace.WithAttributes(attribute.String("cloud.id", req.CloudId)))
+	defer func() { endSpan(span, err) }()
+
 	slClient := shoeslxdpb.NewShoesLXDMultiClient(l.conn)
 	slReq := &shoeslxdpb.DeleteInstanceRequest{
 		CloudId:     req.CloudId,